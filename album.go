@@ -2,10 +2,10 @@ package spotify
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -90,6 +90,8 @@ type FullAlbum struct {
 	SimpleAlbum
 	Copyrights []Copyright `json:"copyrights"`
 	Genres     []string    `json:"genres"`
+	// The label associated with the album.
+	Label string `json:"label"`
 	// The popularity of the album, represented as an integer between 0 and 100,
 	// with 100 being the most popular.  Popularity of an album is calculated
 	// from the popularity of the album's individual tracks.
@@ -112,6 +114,11 @@ type SavedAlbum struct {
 //
 // [Spotify ID]: https://developer.spotify.com/documentation/web-api/concepts/spotify-uris-ids
 func (c *Client) GetAlbum(ctx context.Context, id ID, opts ...RequestOption) (*FullAlbum, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := fmt.Sprintf("%salbums/%s", c.baseURL, id)
 
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
@@ -120,7 +127,7 @@ func (c *Client) GetAlbum(ctx context.Context, id ID, opts ...RequestOption) (*F
 
 	var a FullAlbum
 
-	err := c.get(ctx, spotifyURL, &a)
+	err = c.get(ctx, spotifyURL, &a)
 	if err != nil {
 		return nil, err
 	}
@@ -128,6 +135,38 @@ func (c *Client) GetAlbum(ctx context.Context, id ID, opts ...RequestOption) (*F
 	return &a, nil
 }
 
+// AlbumGenres returns the genres of an album merged with the genres of its
+// primary (first listed) artist, without duplicates.  Spotify rarely
+// classifies albums, so the album's own genres are often empty, and this is
+// usually more useful for tagging albums or seeding recommendations.  The
+// album's genres are listed first.
+func (c *Client) AlbumGenres(ctx context.Context, albumID ID) ([]string, error) {
+	album, err := c.GetAlbum(ctx, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := album.Genres
+	if len(album.Artists) > 0 {
+		artistGenres, err := c.GetArtistGenres(ctx, album.Artists[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		genres = append(genres[:len(genres):len(genres)], artistGenres...)
+	}
+
+	seen := make(map[string]bool, len(genres))
+	result := []string{}
+	for _, genre := range genres {
+		if !seen[genre] {
+			seen[genre] = true
+			result = append(result, genre)
+		}
+	}
+
+	return result, nil
+}
+
 func toStringSlice(ids []ID) []string {
 	result := make([]string, len(ids))
 	for i, str := range ids {
@@ -136,34 +175,47 @@ func toStringSlice(ids []ID) []string {
 	return result
 }
 
+// maxAlbumsPerRequest is the number of album IDs Spotify accepts in a single
+// request for several albums.
+const maxAlbumsPerRequest = 20
+
 // GetAlbums gets Spotify Catalog information for [multiple albums], given their
-// [Spotify ID]s.  It supports up to 20 IDs in a single call.  Albums are returned
-// in the order requested.  If an album is not found, that position in the
-// result slice will be nil.
+// [Spotify ID]s.  Spotify supports up to 20 IDs in a single request, so albums
+// are requested in batches of 20.  Albums are returned in the order requested.
+// If an album is not found, that position in the result slice will be nil.
 //
 // Supported options: [Market].
 //
 // [multiple albums]: https://developer.spotify.com/documentation/web-api/reference/get-multiple-albums
 // [Spotify ID]: https://developer.spotify.com/documentation/web-api/concepts/spotify-uris-ids
 func (c *Client) GetAlbums(ctx context.Context, ids []ID, opts ...RequestOption) ([]*FullAlbum, error) {
-	if len(ids) > 20 {
-		return nil, errors.New("spotify: exceeded maximum number of albums")
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
-	params := processOptions(opts...).urlParams
-	params.Set("ids", strings.Join(toStringSlice(ids), ","))
 
-	spotifyURL := fmt.Sprintf("%salbums?%s", c.baseURL, params.Encode())
+	albums := make([]*FullAlbum, 0, len(ids))
+	err = c.fetchChunks(ids, maxAlbumsPerRequest, "albums", func(chunk []ID) (int, error) {
+		params := processOptions(opts...).urlParams
+		params.Set("ids", strings.Join(toStringSlice(chunk), ","))
 
-	var a struct {
-		Albums []*FullAlbum `json:"albums"`
-	}
+		spotifyURL := fmt.Sprintf("%salbums?%s", c.baseURL, params.Encode())
+
+		var a struct {
+			Albums []*FullAlbum `json:"albums"`
+		}
 
-	err := c.get(ctx, spotifyURL, &a)
+		if err := c.get(ctx, spotifyURL, &a); err != nil {
+			return 0, err
+		}
+		albums = append(albums, a.Albums...)
+		return len(a.Albums), nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return a.Albums, nil
+	return albums, nil
 }
 
 // AlbumType represents the type of an album. It can be used to filter
@@ -205,6 +257,11 @@ func (at AlbumType) encode() string {
 //
 // [tracks]: https://developer.spotify.com/documentation/web-api/reference/get-an-albums-tracks
 func (c *Client) GetAlbumTracks(ctx context.Context, id ID, opts ...RequestOption) (*SimpleTrackPage, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := fmt.Sprintf("%salbums/%s/tracks", c.baseURL, id)
 
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
@@ -212,10 +269,82 @@ func (c *Client) GetAlbumTracks(ctx context.Context, id ID, opts ...RequestOptio
 	}
 
 	var result SimpleTrackPage
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &result)
 	if err != nil {
 		return nil, err
 	}
 
 	return &result, nil
 }
+
+// GetTracksForAlbums gets all of the tracks for each of the specified albums,
+// keyed by album ID.  Albums are fetched in batches of 20 with a bounded
+// number of concurrent requests, and the tracks of albums with more tracks
+// than fit in a single response are paged through until complete.  Tracks
+// are listed in the order in which they appear on the album.
+//
+// Albums that are not found are omitted from the result.  If some of the
+// albums can't be fetched, the tracks of the others are still returned, along
+// with a [BatchError] that identifies the albums that failed.
+//
+// Supported options: [Market].
+func (c *Client) GetTracksForAlbums(ctx context.Context, albumIDs []ID, opts ...RequestOption) (map[ID][]SimpleTrack, error) {
+	if err := c.checkChunkSize(len(albumIDs), maxAlbumsPerRequest); err != nil {
+		return nil, err
+	}
+
+	chunks := chunkIDs(albumIDs, maxAlbumsPerRequest)
+
+	var mu sync.Mutex
+	result := make(map[ID][]SimpleTrack, len(albumIDs))
+	errs := make([]error, len(albumIDs))
+
+	chunkErrs := forEachConcurrent(ctx, len(chunks), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		albums, err := c.GetAlbums(ctx, chunks[i], opts...)
+		if err != nil {
+			return err
+		}
+
+		for j, album := range albums {
+			if album == nil {
+				continue
+			}
+			tracks, err := c.allAlbumTracks(ctx, &album.Tracks)
+			if err != nil {
+				errs[i*maxAlbumsPerRequest+j] = err
+				continue
+			}
+
+			mu.Lock()
+			result[album.ID] = tracks
+			mu.Unlock()
+		}
+		return nil
+	})
+	for i, err := range chunkErrs {
+		if err == nil {
+			continue
+		}
+		for j := range chunks[i] {
+			errs[i*maxAlbumsPerRequest+j] = err
+		}
+	}
+
+	return result, newBatchError(albumIDs, errs)
+}
+
+// allAlbumTracks collects the tracks in page and every page after it.
+func (c *Client) allAlbumTracks(ctx context.Context, page *SimpleTrackPage) ([]SimpleTrack, error) {
+	tracks := make([]SimpleTrack, 0, page.Total)
+	for {
+		tracks = append(tracks, page.Tracks...)
+
+		err := c.NextPage(ctx, page)
+		if err == ErrNoMorePages {
+			return tracks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
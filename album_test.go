@@ -2,7 +2,10 @@ package spotify
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -77,6 +80,9 @@ func TestFindAlbums(t *testing.T) {
 		t.Errorf("Expected release 2013-11-08, got %d-%02d-%02d\n",
 			release.Year(), release.Month(), release.Day())
 	}
+	if res[3].Label != "Warp Records" {
+		t.Errorf("Expected label Warp Records, got '%s'\n", res[3].Label)
+	}
 	releaseMonthPrecision := res[3].ReleaseDateTime()
 	if releaseMonthPrecision.Year() != 2007 ||
 		releaseMonthPrecision.Month() != 3 ||
@@ -86,6 +92,25 @@ func TestFindAlbums(t *testing.T) {
 	}
 }
 
+func TestGetAlbumsInChunks(t *testing.T) {
+	testChunks(t, chunkTest{
+		key:    "albums",
+		size:   maxAlbumsPerRequest,
+		n:      45,
+		market: CountryUnitedKingdom,
+		get: func(c *Client, ids []ID) ([]ID, error) {
+			albums, err := c.GetAlbums(context.Background(), ids, Market(CountryUnitedKingdom))
+			got := make([]ID, len(albums))
+			for i, album := range albums {
+				if album != nil {
+					got[i] = album.ID
+				}
+			}
+			return got, err
+		},
+	})
+}
+
 func TestFindAlbumTracks(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/find_album_tracks.txt")
 	defer server.Close()
@@ -105,3 +130,134 @@ func TestFindAlbumTracks(t *testing.T) {
 		t.Error("Expected 1 track, got", len(res.Tracks))
 	}
 }
+
+func TestGetAlbumTracksDiscAndTrackNumbers(t *testing.T) {
+	client, server := testClientFile(http.StatusOK, "test_data/album_tracks_multi_disc.txt")
+	defer server.Close()
+
+	res, err := client.GetAlbumTracks(context.Background(), ID("6dVIqQ8qmQ5GBnJ9shOYGE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		disc, track Numeric
+	}{{1, 1}, {1, 2}, {2, 1}}
+	if len(res.Tracks) != len(expected) {
+		t.Fatalf("Got %d tracks, expected %d", len(res.Tracks), len(expected))
+	}
+	for i, track := range res.Tracks {
+		if track.DiscNumber != expected[i].disc || track.TrackNumber != expected[i].track {
+			t.Errorf("Track %d: got disc %d track %d, expected disc %d track %d", i,
+				track.DiscNumber, track.TrackNumber, expected[i].disc, expected[i].track)
+		}
+	}
+}
+
+func TestGetTracksForAlbums(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/albums", func(w http.ResponseWriter, r *http.Request) {
+		if ids := r.URL.Query().Get("ids"); ids != "album1,album2,missing" {
+			t.Errorf("Unexpected IDs: %s", ids)
+		}
+		fmt.Fprintf(w, `{ "albums": [
+			{ "id": "album1", "tracks": {
+				"items": [ { "id": "track1" }, { "id": "track2" } ],
+				"next": "http://%s/albums/album1/tracks?offset=2&limit=2",
+				"total": 3
+			} },
+			{ "id": "album2", "tracks": { "items": [ { "id": "track4" } ], "next": null, "total": 1 } },
+			null
+		] }`, r.Host)
+	})
+	mux.HandleFunc("/albums/album1/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "items": [ { "id": "track3" } ], "next": null, "offset": 2, "total": 3 }`)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+
+	res, err := client.GetTracksForAlbums(context.Background(), []ID{"album1", "album2", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("Expected 2 albums, got %d", len(res))
+	}
+	want := map[ID][]ID{
+		"album1": {"track1", "track2", "track3"},
+		"album2": {"track4"},
+	}
+	for album, ids := range want {
+		tracks := res[album]
+		if len(tracks) != len(ids) {
+			t.Errorf("Expected %d tracks for %s, got %d", len(ids), album, len(tracks))
+			continue
+		}
+		for i, id := range ids {
+			if tracks[i].ID != id {
+				t.Errorf("Expected track %d of %s to be %s, got %s", i, album, id, tracks[i].ID)
+			}
+		}
+	}
+}
+
+func TestGetTracksForAlbumsPartialFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/albums", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{ "albums": [
+			{ "id": "album1", "tracks": {
+				"items": [ { "id": "track1" } ],
+				"next": "http://%s/albums/album1/tracks?offset=1",
+				"total": 2
+			} },
+			{ "id": "album2", "tracks": { "items": [ { "id": "track3" } ], "next": null, "total": 1 } }
+		] }`, r.Host)
+	})
+	mux.HandleFunc("/albums/album1/tracks", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Bad gateway", http.StatusBadGateway)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+
+	res, err := client.GetTracksForAlbums(context.Background(), []ID{"album1", "album2"})
+	var be BatchError
+	if !errors.As(err, &be) {
+		t.Fatal("Expected a BatchError, got", err)
+	}
+	if len(be.Errors) != 1 || be.Errors[0].ID != "album1" || be.Errors[0].Index != 0 {
+		t.Errorf("Unexpected item errors: %v", be.Errors)
+	}
+	var se Error
+	if !errors.As(err, &se) || se.Status != http.StatusBadGateway {
+		t.Error("Expected the spotify error to be reachable, got", err)
+	}
+	if _, ok := res["album1"]; ok {
+		t.Error("Expected the failed album to be omitted")
+	}
+	if tracks := res["album2"]; len(tracks) != 1 || tracks[0].ID != "track3" {
+		t.Error("Expected the tracks of the successful album, got", tracks)
+	}
+}
+
+func TestAlbumGenres(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/albums/album1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "id": "album1", "genres": [ "prog rock", "art rock" ], "artists": [ { "id": "artist1" }, { "id": "artist2" } ] }`)
+	})
+	mux.HandleFunc("/artists/artist1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "id": "artist1", "genres": [ "art rock", "psychedelic rock" ] }`)
+	})
+	mux.HandleFunc("/artists/artist2", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Only the primary artist's genres should be requested")
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+
+	genres, err := client.AlbumGenres(context.Background(), "album1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"prog rock", "art rock", "psychedelic rock"}
+	if strings.Join(genres, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected genres %v, got %v", want, genres)
+	}
+}
@@ -2,6 +2,7 @@ package spotify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -25,12 +26,18 @@ type FullArtist struct {
 	Popularity Numeric `json:"popularity"`
 	// A list of genres the artist is associated with.  For example, "Prog Rock"
 	// or "Post-Grunge".  If not yet classified, the slice is empty.
-	Genres    []string  `json:"genres"`
+	Genres []string `json:"genres"`
+	// Information about the followers of the artist.
 	Followers Followers `json:"followers"`
 	// Images of the artist in various sizes, widest first.
 	Images []Image `json:"images"`
 }
 
+// FollowerCount returns the total number of followers of the artist.
+func (a *FullArtist) FollowerCount() int64 {
+	return int64(a.Followers.Count)
+}
+
 // GetArtist gets Spotify catalog information for a single artist, given its Spotify ID.
 func (c *Client) GetArtist(ctx context.Context, id ID) (*FullArtist, error) {
 	spotifyURL := fmt.Sprintf("%sartists/%s", c.baseURL, id)
@@ -44,33 +51,63 @@ func (c *Client) GetArtist(ctx context.Context, id ID) (*FullArtist, error) {
 	return &a, nil
 }
 
+// GetArtistGenres returns the genres associated with the specified artist.
+// If the artist has not been classified yet, the slice is empty.  Use
+// [GetArtistGenreSeeds] to only get genres that can be used as seeds for
+// [GetRecommendations].
+func (c *Client) GetArtistGenres(ctx context.Context, id ID) ([]string, error) {
+	a, err := c.GetArtist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Genres, nil
+}
+
+// maxArtistsPerRequest is the number of artist IDs Spotify accepts in a
+// single request for several artists.
+const maxArtistsPerRequest = 50
+
 // GetArtists gets spotify catalog information for several artists based on their
-// Spotify IDs.  It supports up to 50 artists in a single call.  Artists are
-// returned in the order requested.  If an artist is not found, that position
-// in the result will be nil.  Duplicate IDs will result in duplicate artists
-// in the result.
+// Spotify IDs.  Spotify supports up to 50 artists in a single request, so
+// artists are requested in batches of 50.  Artists are returned in the order
+// requested.  If an artist is not found, that position in the result will be
+// nil.  Duplicate IDs will result in duplicate artists in the result.
 func (c *Client) GetArtists(ctx context.Context, ids ...ID) ([]*FullArtist, error) {
-	spotifyURL := fmt.Sprintf("%sartists?ids=%s", c.baseURL, strings.Join(toStringSlice(ids), ","))
+	artists := make([]*FullArtist, 0, len(ids))
+	err := c.fetchChunks(ids, maxArtistsPerRequest, "artists", func(chunk []ID) (int, error) {
+		spotifyURL := fmt.Sprintf("%sartists?ids=%s", c.baseURL, strings.Join(toStringSlice(chunk), ","))
 
-	var a struct {
-		Artists []*FullArtist
-	}
+		var a struct {
+			Artists []*FullArtist
+		}
 
-	err := c.get(ctx, spotifyURL, &a)
+		if err := c.get(ctx, spotifyURL, &a); err != nil {
+			return 0, err
+		}
+		artists = append(artists, a.Artists...)
+		return len(a.Artists), nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return a.Artists, nil
+	return artists, nil
 }
 
 // GetArtistsTopTracks gets Spotify catalog information about an artist's top
 // tracks in a particular country.  It returns a maximum of 10 tracks.  The
-// country is specified as an [ISO 3166-1 alpha-2] country code.
+// country is specified as an [ISO 3166-1 alpha-2] country code, and is sent to
+// Spotify as the market.  Spotify only ranks top tracks within a market, so the
+// country is required.
 //
 // [ISO 3166-1 alpha-2]: https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2
 func (c *Client) GetArtistsTopTracks(ctx context.Context, artistID ID, country string) ([]FullTrack, error) {
-	spotifyURL := fmt.Sprintf("%sartists/%s/top-tracks?country=%s", c.baseURL, artistID, country)
+	if country == "" {
+		return nil, errors.New("spotify: a country is required to get an artist's top tracks")
+	}
+
+	spotifyURL := fmt.Sprintf("%sartists/%s/top-tracks?market=%s", c.baseURL, artistID, country)
 
 	var t struct {
 		Tracks []FullTrack `json:"tracks"`
@@ -104,33 +141,33 @@ func (c *Client) GetRelatedArtists(ctx context.Context, id ID) ([]FullArtist, er
 }
 
 // GetArtistAlbums gets Spotify catalog information about an artist's albums.
-// It is equivalent to GetArtistAlbumsOpt(artistID, nil).
+// The returned page can be passed to [Client.NextPage] to get the remaining
+// albums.
 //
-// The AlbumType argument can be used to find a particular types of album.
+// The ts argument can be used to find particular types of album, as can the
+// [AlbumGroups] option, which takes precedence if both are given.  If ts is
+// nil and no AlbumGroups option is given, albums of all types are returned.
 // If the Market is not specified, Spotify will likely return a lot
-// of duplicates (one for each market in which the album is available
+// of duplicates (one for each market in which the album is available).
 //
-// Supported options: [Market].
+// Supported options: [Market], [Limit], [Offset], [AlbumGroups].
 func (c *Client) GetArtistAlbums(ctx context.Context, artistID ID, ts []AlbumType, opts ...RequestOption) (*SimpleAlbumPage, error) {
-	spotifyURL := fmt.Sprintf("%sartists/%s/albums", c.baseURL, artistID)
-	// add optional query string if options were specified
-	values := processOptions(opts...).urlParams
-
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 	if ts != nil {
-		types := make([]string, len(ts))
-		for i := range ts {
-			types[i] = ts[i].encode()
-		}
-		values.Set("include_groups", strings.Join(types, ","))
+		opts = append([]RequestOption{AlbumGroups(ts...)}, opts...)
 	}
 
-	if query := values.Encode(); query != "" {
-		spotifyURL += "?" + query
+	spotifyURL := fmt.Sprintf("%sartists/%s/albums", c.baseURL, artistID)
+	if params := processOptions(opts...).urlParams.Encode(); params != "" {
+		spotifyURL += "?" + params
 	}
 
 	var p SimpleAlbumPage
 
-	err := c.get(ctx, spotifyURL, &p)
+	err = c.get(ctx, spotifyURL, &p)
 	if err != nil {
 		return nil, err
 	}
@@ -16,7 +16,10 @@ package spotify
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -90,6 +93,9 @@ func TestFindArtist(t *testing.T) {
 	if followers := artist.Followers.Count; followers != 2265279 {
 		t.Errorf("Got %d followers, want 2265279\n", followers)
 	}
+	if followers := artist.FollowerCount(); followers != 2265279 {
+		t.Errorf("Got FollowerCount %d, want 2265279\n", followers)
+	}
 	if artist.Name != "Pitbull" {
 		t.Error("Got ", artist.Name, ", wanted Pitbull")
 	}
@@ -116,6 +122,36 @@ func TestArtistTopTracks(t *testing.T) {
 	}
 }
 
+func TestArtistTopTracksRequiresCountry(t *testing.T) {
+	client, server := testClientString(http.StatusOK, "", func(*http.Request) {
+		t.Error("No request should be made without a country")
+	})
+	defer server.Close()
+
+	_, err := client.GetArtistsTopTracks(context.Background(), ID("43ZHCT0cAZBISjO8DG9PnE"), "")
+	if err == nil {
+		t.Error("Expected an error without a country")
+	}
+}
+
+func TestGetArtistsInChunks(t *testing.T) {
+	testChunks(t, chunkTest{
+		key:  "artists",
+		size: maxArtistsPerRequest,
+		n:    120,
+		get: func(c *Client, ids []ID) ([]ID, error) {
+			artists, err := c.GetArtists(context.Background(), ids...)
+			got := make([]ID, len(artists))
+			for i, artist := range artists {
+				if artist != nil {
+					got[i] = artist.ID
+				}
+			}
+			return got, err
+		},
+	})
+}
+
 func TestRelatedArtists(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/related_artists.txt")
 	defer server.Close()
@@ -185,3 +221,46 @@ func TestArtistAlbumsFiltered(t *testing.T) {
 		t.Errorf("Wrong Spotify external URL: want %s, got %s\n", url, spotifyURL)
 	}
 }
+
+func TestArtistAlbumsWithAlbumGroups(t *testing.T) {
+	var query url.Values
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, albumsResponse)
+	}))
+	defer server.Close()
+
+	albums, err := client.GetArtistAlbums(context.Background(), "1vCWHaC5f2uS3yhpwWbIA6", nil,
+		AlbumGroups(AlbumTypeSingle, AlbumTypeAlbum), Market(CountryUnitedKingdom), Limit(2), Offset(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"include_groups": {"album,single"},
+		"market":         {CountryUnitedKingdom},
+		"limit":          {"2"},
+		"offset":         {"4"},
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("Expected query %v, got %v", want, query)
+	}
+	if albums.Total != 157 || albums.Next == "" {
+		t.Errorf("Expected the paging fields to be decoded, got total %d and next '%s'", albums.Total, albums.Next)
+	}
+
+	_, err = client.GetArtistAlbums(context.Background(), "1vCWHaC5f2uS3yhpwWbIA6", []AlbumType{AlbumTypeAppearsOn}, AlbumGroups(AlbumTypeCompilation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups := query.Get("include_groups"); groups != "compilation" {
+		t.Errorf("Expected the AlbumGroups option to take precedence, got '%s'", groups)
+	}
+
+	_, err = client.GetArtistAlbums(context.Background(), "1vCWHaC5f2uS3yhpwWbIA6", []AlbumType{AlbumTypeAppearsOn}, AlbumGroups())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups := query.Get("include_groups"); groups != "appears_on" {
+		t.Errorf("Expected an empty AlbumGroups option to be ignored, got '%s'", groups)
+	}
+}
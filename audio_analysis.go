@@ -3,6 +3,7 @@ package spotify
 import (
 	"context"
 	"fmt"
+	"sort"
 )
 
 // AudioAnalysis contains a [detailed audio analysis] for a single track
@@ -28,6 +29,11 @@ type Marker struct {
 	Confidence float64 `json:"confidence"`
 }
 
+// End returns the time, in seconds, at which the marker ends.
+func (m Marker) End() float64 {
+	return m.Start + m.Duration
+}
+
 // AnalysisMeta describes details about Spotify's audio analysis of the track
 type AnalysisMeta struct {
 	AnalyzerVersion string  `json:"analyzer_version"`
@@ -97,6 +103,21 @@ type AnalysisTrack struct {
 	RhythmVersion           float64 `json:"rhythm_version"`
 }
 
+// SegmentAt returns the segment that is playing at the given position in the
+// track, in seconds, such as the progress reported by the player, or nil if
+// there is none.  The segments are ordered by their start, so it finds the
+// segment with a binary search, which is cheap enough to call on every frame
+// of a visualization.
+func (a *AudioAnalysis) SegmentAt(seconds float64) *Segment {
+	i := sort.Search(len(a.Segments), func(i int) bool {
+		return a.Segments[i].Start > seconds
+	}) - 1
+	if i < 0 || seconds >= a.Segments[i].End() {
+		return nil
+	}
+	return &a.Segments[i]
+}
+
 // GetAudioAnalysis queries the Spotify web API for an [audio analysis] of a
 // single track.
 //
@@ -140,3 +140,41 @@ func TestAudioAnalysis(t *testing.T) {
 		t.Errorf(fieldsDifferTemplate, "Tatums")
 	}
 }
+
+func TestSegmentAt(t *testing.T) {
+	analysis := AudioAnalysis{
+		Segments: []Segment{
+			{Marker: Marker{Start: 0, Duration: 0.5}, LoudnessMax: -10},
+			{Marker: Marker{Start: 0.5, Duration: 0.25}, LoudnessMax: -20},
+			{Marker: Marker{Start: 1, Duration: 1}, LoudnessMax: -30},
+		},
+	}
+
+	testTable := []struct {
+		Seconds  float64
+		Loudness float64 // zero for no segment
+	}{
+		{-1, 0},
+		{0, -10},
+		{0.49, -10},
+		{0.5, -20},
+		{0.8, 0},
+		{1.5, -30},
+		{2, 0},
+	}
+	for _, tt := range testTable {
+		segment := analysis.SegmentAt(tt.Seconds)
+		if tt.Loudness == 0 {
+			if segment != nil {
+				t.Errorf("Expected no segment at %v, got one starting at %v", tt.Seconds, segment.Start)
+			}
+			continue
+		}
+		if segment == nil || segment.LoudnessMax != tt.Loudness {
+			t.Errorf("Expected the segment with loudness %v at %v, got %+v", tt.Loudness, tt.Seconds, segment)
+		}
+	}
+	if end := analysis.Segments[1].End(); end != 0.75 {
+		t.Errorf("Expected the segment to end at 0.75, got %v", end)
+	}
+}
@@ -108,11 +108,41 @@ const (
 	Major
 )
 
+// maxAudioFeaturesPerRequest is the number of track IDs Spotify accepts in a
+// single request for audio features.
+const maxAudioFeaturesPerRequest = 100
+
 // GetAudioFeatures queries the Spotify Web API for various
 // high-level acoustic attributes of audio tracks.
 // Objects are returned in the order requested.  If an object
 // is not found, a nil value is returned in the appropriate position.
+//
+// Spotify supports up to 100 IDs in a single request, so with more IDs the
+// features are requested in batches of 100.
 func (c *Client) GetAudioFeatures(ctx context.Context, ids ...ID) ([]*AudioFeatures, error) {
+	if len(ids) <= maxAudioFeaturesPerRequest {
+		return c.getAudioFeatures(ctx, ids)
+	}
+
+	features := make([]*AudioFeatures, 0, len(ids))
+	err := c.fetchChunks(ids, maxAudioFeaturesPerRequest, "audio features", func(chunk []ID) (int, error) {
+		f, err := c.getAudioFeatures(ctx, chunk)
+		if err != nil {
+			return 0, err
+		}
+		features = append(features, f...)
+		return len(f), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// getAudioFeatures requests the audio features of at most
+// maxAudioFeaturesPerRequest tracks.
+func (c *Client) getAudioFeatures(ctx context.Context, ids []ID) ([]*AudioFeatures, error) {
 	url := fmt.Sprintf("%saudio-features?ids=%s", c.baseURL, strings.Join(toStringSlice(ids), ","))
 
 	temp := struct {
@@ -126,3 +156,109 @@ func (c *Client) GetAudioFeatures(ctx context.Context, ids ...ID) ([]*AudioFeatu
 
 	return temp.F, nil
 }
+
+// GetTrackAudioFeatures gets the audio features of a single track.
+func (c *Client) GetTrackAudioFeatures(ctx context.Context, id ID) (*AudioFeatures, error) {
+	url := fmt.Sprintf("%saudio-features/%s", c.baseURL, id)
+
+	var f AudioFeatures
+	err := c.get(ctx, url, &f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// AudioFeaturesAverage holds the mean of each numeric attribute of
+// [AudioFeatures] over a set of tracks.  See [AudioFeatures] for the meaning
+// of each attribute.
+type AudioFeaturesAverage struct {
+	// Count is the number of tracks that the averages were computed from.
+	// Tracks without audio features are not counted.
+	Count int
+
+	Acousticness     float64
+	Danceability     float64
+	Duration         float64
+	Energy           float64
+	Instrumentalness float64
+	// Key is the mean pitch class.  Since pitch classes wrap around, it is
+	// rarely meaningful on its own.
+	Key      float64
+	Liveness float64
+	Loudness float64
+	// Mode is the fraction of the tracks in a major key.
+	Mode          float64
+	Speechiness   float64
+	Tempo         float64
+	TimeSignature float64
+	Valence       float64
+}
+
+// add adds f to the running totals in a.
+func (a *AudioFeaturesAverage) add(f *AudioFeatures) {
+	a.Count++
+	a.Acousticness += float64(f.Acousticness)
+	a.Danceability += float64(f.Danceability)
+	a.Duration += float64(f.Duration)
+	a.Energy += float64(f.Energy)
+	a.Instrumentalness += float64(f.Instrumentalness)
+	a.Key += float64(f.Key)
+	a.Liveness += float64(f.Liveness)
+	a.Loudness += float64(f.Loudness)
+	a.Mode += float64(f.Mode)
+	a.Speechiness += float64(f.Speechiness)
+	a.Tempo += float64(f.Tempo)
+	a.TimeSignature += float64(f.TimeSignature)
+	a.Valence += float64(f.Valence)
+}
+
+// divide turns the totals in a into means.
+func (a *AudioFeaturesAverage) divide() {
+	if a.Count == 0 {
+		return
+	}
+	n := float64(a.Count)
+	a.Acousticness /= n
+	a.Danceability /= n
+	a.Duration /= n
+	a.Energy /= n
+	a.Instrumentalness /= n
+	a.Key /= n
+	a.Liveness /= n
+	a.Loudness /= n
+	a.Mode /= n
+	a.Speechiness /= n
+	a.Tempo /= n
+	a.TimeSignature /= n
+	a.Valence /= n
+}
+
+// AverageAudioFeatures gets the audio features of the specified tracks and
+// returns the mean of each numeric attribute, for example to describe how
+// energetic a collection of tracks is as a whole.  Features are requested in
+// batches of 100 tracks with [GetAudioFeatures].  Tracks that Spotify has no
+// audio features for are ignored; if none of the tracks have any, the result
+// has a zero Count and zero averages.
+func (c *Client) AverageAudioFeatures(ctx context.Context, trackIDs ...ID) (*AudioFeaturesAverage, error) {
+	if err := c.checkChunkSize(len(trackIDs), 100); err != nil {
+		return nil, err
+	}
+
+	var avg AudioFeaturesAverage
+	for _, chunk := range chunkIDs(trackIDs, 100) {
+		features, err := c.GetAudioFeatures(ctx, chunk...)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range features {
+			if f != nil {
+				avg.add(f)
+			}
+		}
+	}
+	avg.divide()
+
+	return &avg, nil
+}
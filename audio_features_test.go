@@ -2,7 +2,10 @@ package spotify
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -93,3 +96,81 @@ func TestAudioFeatures(t *testing.T) {
 		t.Errorf("Want key G, got %v\n", features[0].Key)
 	}
 }
+
+func TestAverageAudioFeatures(t *testing.T) {
+	var batches []int
+	c, s := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		batches = append(batches, len(strings.Split(r.URL.Query().Get("ids"), ",")))
+		fmt.Fprint(w, response)
+	}))
+	defer s.Close()
+
+	ids := make([]ID, 150)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", i))
+	}
+	avg, err := c.AverageAudioFeatures(context.Background(), ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || batches[0] != 100 || batches[1] != 50 {
+		t.Errorf("Expected batches of 100 and 50 tracks, got %v", batches)
+	}
+	// each response holds the same three tracks with features, and a null
+	if avg.Count != 6 {
+		t.Errorf("Want 6 tracks, got %d", avg.Count)
+	}
+	if want := (0.626 + 0.815 + 0.402) / 3; math.Abs(avg.Energy-want) > 1e-6 {
+		t.Errorf("Want energy %f, got %f", want, avg.Energy)
+	}
+	if want := (535223.0 + 187800 + 497493) / 3; math.Abs(avg.Duration-want) > 1e-6 {
+		t.Errorf("Want duration %f, got %f", want, avg.Duration)
+	}
+	if avg.Mode != 1 {
+		t.Errorf("Want all tracks in a major key, got %f", avg.Mode)
+	}
+}
+
+func TestAudioFeaturesInChunks(t *testing.T) {
+	testChunks(t, chunkTest{
+		key:  "audio_features",
+		size: maxAudioFeaturesPerRequest,
+		n:    205,
+		get: func(c *Client, ids []ID) ([]ID, error) {
+			features, err := c.GetAudioFeatures(context.Background(), ids...)
+			got := make([]ID, len(features))
+			for i, f := range features {
+				if f != nil {
+					got[i] = f.ID
+				}
+			}
+			return got, err
+		},
+	})
+}
+
+func TestTrackAudioFeatures(t *testing.T) {
+	c, s := testClientString(http.StatusOK, `{
+		"danceability": 0.281,
+		"energy": 0.402,
+		"key": 4,
+		"mode": 1,
+		"tempo": 115.700,
+		"id": "24JygzOLM0EmRQeGtFcIcG",
+		"duration_ms": 497493,
+		"time_signature": 3
+	}`, func(r *http.Request) {
+		if r.URL.Path != "/audio-features/24JygzOLM0EmRQeGtFcIcG" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+	defer s.Close()
+
+	f, err := c.GetTrackAudioFeatures(context.Background(), "24JygzOLM0EmRQeGtFcIcG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Key(f.Key) != E || f.TimeSignature != 3 || f.Duration != 497493 {
+		t.Errorf("Unexpected features %#v", f)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 const (
@@ -84,7 +85,8 @@ const (
 //	token, err := a.Token(state, r)
 //	client := a.Client(token)
 type Authenticator struct {
-	config *oauth2.Config
+	config     *oauth2.Config
+	httpClient *http.Client
 }
 
 type AuthenticatorOption func(a *Authenticator)
@@ -120,6 +122,16 @@ func WithRedirectURL(url string) AuthenticatorOption {
 	}
 }
 
+// WithHTTPClient configures the HTTP client used to request and refresh tokens
+// from the Spotify Accounts Service.  Without this [net/http.DefaultClient] is
+// used, unless the context passed to a method carries a client as described
+// for [golang.org/x/oauth2.HTTPClient].
+func WithHTTPClient(client *http.Client) AuthenticatorOption {
+	return func(a *Authenticator) {
+		a.httpClient = client
+	}
+}
+
 // New creates an authenticator which is used to implement the OAuth2 authorization flow.
 //
 // By default, it pulls your client ID and secret key from the SPOTIFY_ID and SPOTIFY_SECRET environment variables.
@@ -174,24 +186,83 @@ func (a Authenticator) Token(ctx context.Context, state string, r *http.Request,
 	if actualState != state {
 		return nil, errors.New("spotify: redirect state parameter doesn't match")
 	}
-	return a.config.Exchange(ctx, code, opts...)
+	return a.tokenConfig().Exchange(a.tokenContext(ctx), code, opts...)
 }
 
 // RefreshToken returns a new token if an access token has expired.
 // If it has not expired, return the existing token.
 func (a Authenticator) RefreshToken(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
-	src := a.config.TokenSource(ctx, token)
+	src := a.tokenConfig().TokenSource(a.tokenContext(ctx), token)
 	return src.Token()
 }
 
 // Exchange is like [Token], except it allows you to manually specify the access
 // code instead of pulling it out of an HTTP request.
 func (a Authenticator) Exchange(ctx context.Context, code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
-	return a.config.Exchange(ctx, code, opts...)
+	return a.tokenConfig().Exchange(a.tokenContext(ctx), code, opts...)
 }
 
 // Client creates a [net/http.Client] that will use the specified access token
 // for its API requests. You will typically pass this to [github.com/zmb3/spotify.New].
 func (a Authenticator) Client(ctx context.Context, token *oauth2.Token) *http.Client {
-	return a.config.Client(ctx, token)
+	return oauth2.NewClient(ctx, a.tokenConfig().TokenSource(a.tokenContext(ctx), token))
+}
+
+// ClientCredentialsToken performs the [Client Credentials] flow, exchanging the
+// client ID and secret for an access token.  The token only grants access to
+// endpoints that don't need a user's authorization, such as search and the
+// catalog of albums, artists and tracks, so no scopes are requested.
+//
+// [Client Credentials]: https://developer.spotify.com/documentation/web-api/tutorials/client-credentials-flow
+func (a Authenticator) ClientCredentialsToken(ctx context.Context) (*oauth2.Token, error) {
+	return a.clientCredentials().Token(a.tokenContext(ctx))
+}
+
+// ClientCredentialsClient creates a [net/http.Client] that authenticates its
+// API requests with the [Client Credentials] flow, requesting a new access
+// token whenever the current one expires.  You will typically pass this to
+// [github.com/zmb3/spotify.New]:
+//
+//	a := spotifyauth.New(spotifyauth.WithClientID(id), spotifyauth.WithClientSecret(secret))
+//	client := spotify.New(a.ClientCredentialsClient(ctx))
+//
+// The first token is requested along with the first API request, so errors
+// such as invalid credentials are returned from it.  Use
+// [Authenticator.ClientCredentialsToken] to check the credentials up front.
+//
+// [Client Credentials]: https://developer.spotify.com/documentation/web-api/tutorials/client-credentials-flow
+func (a Authenticator) ClientCredentialsClient(ctx context.Context) *http.Client {
+	return oauth2.NewClient(ctx, a.clientCredentials().TokenSource(a.tokenContext(ctx)))
+}
+
+func (a Authenticator) clientCredentials() *clientcredentials.Config {
+	return &clientcredentials.Config{
+		ClientID:     a.config.ClientID,
+		ClientSecret: a.config.ClientSecret,
+		TokenURL:     a.config.Endpoint.TokenURL,
+		AuthStyle:    a.config.Endpoint.AuthStyle,
+	}
+}
+
+// tokenConfig returns the configuration to use for requests to the token
+// endpoint.  Public clients, which have no client secret, must send their
+// client ID in the request body, so for them the credentials are sent that
+// way straight away, rather than only after a failed attempt to send them in
+// the Authorization header.
+func (a Authenticator) tokenConfig() *oauth2.Config {
+	if a.config.ClientSecret != "" || a.config.Endpoint.AuthStyle != oauth2.AuthStyleAutoDetect {
+		return a.config
+	}
+	cfg := *a.config
+	cfg.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	return &cfg
+}
+
+// tokenContext returns the context to use for requests to the token endpoint,
+// which carries the client configured with [WithHTTPClient], if any.
+func (a Authenticator) tokenContext(ctx context.Context) context.Context {
+	if a.httpClient == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, a.httpClient)
 }
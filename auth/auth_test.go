@@ -0,0 +1,125 @@
+package spotifyauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientCredentialsClient(t *testing.T) {
+	var tokens int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/token":
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+			if grant := r.PostForm.Get("grant_type"); grant != "client_credentials" {
+				t.Errorf("Expected the client_credentials grant, got %q", grant)
+			}
+			if id, secret, _ := r.BasicAuth(); id != "id" || secret != "secret" {
+				t.Errorf("Unexpected credentials %s:%s", id, secret)
+			}
+			tokens++
+			w.Header().Set("Content-Type", "application/json")
+			// tokens expiring within ten seconds are refreshed, so this one is
+			// refreshed straight away
+			fmt.Fprintf(w, `{ "access_token": "token%d", "token_type": "Bearer", "expires_in": 1 }`, tokens)
+		default:
+			if got, want := r.Header.Get("Authorization"), fmt.Sprintf("Bearer token%d", tokens); got != want {
+				t.Errorf("Expected %q, got %q", want, got)
+			}
+		}
+	}))
+	defer server.Close()
+
+	var tokenRequests int
+	tokenClient := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		tokenRequests++
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	a := New(WithClientID("id"), WithClientSecret("secret"), WithHTTPClient(tokenClient))
+	a.config.Endpoint.TokenURL = server.URL + "/api/token"
+
+	client := a.ClientCredentialsClient(context.Background())
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/v1/search")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if tokens != 2 {
+		t.Errorf("Expected the token to be refreshed, got %d tokens", tokens)
+	}
+	if tokenRequests != 2 {
+		t.Errorf("Expected the token requests to use the custom client, got %d requests", tokenRequests)
+	}
+
+	token, err := a.ClientCredentialsToken(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "token3" {
+		t.Errorf("Unexpected token %s", token.AccessToken)
+	}
+}
+
+func TestPKCE(t *testing.T) {
+	verifier := "w0HfYrKnG8AihqYHA9_XUPTIcqEXQvCQfOF2IitRgmlF43YWJ8dy2b49ZUwVUOR.YnvzVoTBL57BwIhM4ouSa~tdf0eE_OmiMC_ESCcVOe7maSLIk9IOdBhRstAxjCl7"
+	if got := CodeChallenge(verifier); got != "ZhZJzPQXYBMjH8FlGAdYK5AndohLzFfZT-8J7biT7ig" {
+		t.Errorf("Unexpected challenge %s", got)
+	}
+	generated, challenge, err := GenerateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(generated) < 43 || len(generated) > 128 || challenge != CodeChallenge(generated) {
+		t.Errorf("Unexpected verifier %s with challenge %s", generated, challenge)
+	}
+
+	a := New(WithClientID("id"), WithClientSecret(""), WithRedirectURL("http://localhost/callback"))
+	authURL, err := url.Parse(a.AuthURLWithPKCE("state", challenge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := authURL.Query()
+	if query.Get("code_challenge") != challenge || query.Get("code_challenge_method") != "S256" || query.Get("client_id") != "id" {
+		t.Errorf("Unexpected authorization URL %s", authURL)
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Error("Expected no Authorization header for a public client")
+		}
+		if r.PostForm.Get("code_verifier") != generated || r.PostForm.Get("code") != "code" || r.PostForm.Get("client_id") != "id" {
+			t.Errorf("Unexpected token request %v", r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{ "access_token": "token", "token_type": "Bearer", "expires_in": 3600, "refresh_token": "refresh" }`)
+	}))
+	defer server.Close()
+	a.config.Endpoint.TokenURL = server.URL
+
+	token, err := a.Exchange(context.Background(), "code", CodeVerifier(generated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "token" || requests != 1 {
+		t.Errorf("Unexpected token %s after %d requests", token.AccessToken, requests)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,58 @@
+package spotifyauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"golang.org/x/oauth2"
+)
+
+// GenerateCodeVerifier returns a random code verifier and its S256 code
+// challenge, for the [Authorization Code with PKCE] flow.  This flow is meant
+// for apps that can't keep a client secret, such as mobile and command line
+// apps, so [WithClientSecret] isn't needed.  Generate a new pair for each
+// authorization, pass the challenge to [Authenticator.AuthURLWithPKCE], and
+// keep the verifier until the code is exchanged for a token:
+//
+//	verifier, challenge, err := spotifyauth.GenerateCodeVerifier()
+//	http.Redirect(w, r, a.AuthURLWithPKCE(state, challenge), http.StatusFound)
+//
+//	// then, in redirect handler:
+//	token, err := a.Token(ctx, state, r, spotifyauth.CodeVerifier(verifier))
+//
+// [Authorization Code with PKCE]: https://developer.spotify.com/documentation/web-api/tutorials/code-pkce-flow
+func GenerateCodeVerifier() (verifier, challenge string, err error) {
+	// 64 random bytes encode to 86 characters, within the 43 to 128
+	// characters allowed for a verifier
+	b := make([]byte, 64)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	verifier = base64.RawURLEncoding.EncodeToString(b)
+	return verifier, CodeChallenge(verifier), nil
+}
+
+// CodeChallenge returns the S256 code challenge for a code verifier: the
+// unpadded base64url encoding of the verifier's SHA-256 hash.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthURLWithPKCE is like [Authenticator.AuthURL], but starts the
+// Authorization Code with PKCE flow with the given S256 code challenge.
+// See [GenerateCodeVerifier].
+func (a Authenticator) AuthURLWithPKCE(state, challenge string, opts ...oauth2.AuthCodeOption) string {
+	opts = append(opts,
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		oauth2.SetAuthURLParam("code_challenge", challenge),
+	)
+	return a.config.AuthCodeURL(state, opts...)
+}
+
+// CodeVerifier passes the code verifier of the Authorization Code with PKCE
+// flow to [Authenticator.Token] or [Authenticator.Exchange].
+func CodeVerifier(verifier string) oauth2.AuthCodeOption {
+	return oauth2.SetAuthURLParam("code_verifier", verifier)
+}
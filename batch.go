@@ -0,0 +1,193 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// maxConcurrentRequests bounds the number of requests that the batch helpers
+// in this package issue in parallel.
+const maxConcurrentRequests = 4
+
+// TooManyIDsError is returned by a client configured with [WithoutAutoChunk]
+// when a method is given more items than Spotify accepts in a single request.
+// Its value is the maximum number of items per request.
+type TooManyIDsError int
+
+func (e TooManyIDsError) Error() string {
+	return fmt.Sprintf("spotify: at most %d IDs are allowed per request", int(e))
+}
+
+// checkChunkSize returns a [TooManyIDsError] if the client has auto-chunking
+// disabled and n items exceed the limit of a single request.
+func (c *Client) checkChunkSize(n, limit int) error {
+	if c.noAutoChunk && n > limit {
+		return TooManyIDsError(limit)
+	}
+	return nil
+}
+
+// chunkIDs splits ids into consecutive slices of at most size IDs each,
+// preserving their order (including any duplicates).
+func chunkIDs(ids []ID, size int) [][]ID {
+	chunks := make([][]ID, 0, (len(ids)+size-1)/size)
+	for size < len(ids) {
+		ids, chunks = ids[size:], append(chunks, ids[:size:size])
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
+
+// fetchChunks calls fetch with each chunk of at most size of ids in turn,
+// stopping at the first error.  fetch collects the items of its chunk and
+// returns how many there were, which must be one per ID; noun names the
+// items in the error returned otherwise.  Like [Client.checkChunkSize], it
+// fails without calling fetch if the client has auto-chunking disabled and
+// ids don't fit in a single request.
+func (c *Client) fetchChunks(ids []ID, size int, noun string, fetch func(chunk []ID) (int, error)) error {
+	if err := c.checkChunkSize(len(ids), size); err != nil {
+		return err
+	}
+	for _, chunk := range chunkIDs(ids, size) {
+		n, err := fetch(chunk)
+		if err != nil {
+			return err
+		}
+		if n != len(chunk) {
+			return fmt.Errorf("spotify: got %d %s for %d IDs", n, noun, len(chunk))
+		}
+	}
+	return nil
+}
+
+// BatchItemError is the error for a single item of a batch operation.
+type BatchItemError struct {
+	// Index is the position of the item in the input of the batch operation.
+	Index int
+	// ID is the Spotify ID of the item, if it has one.
+	ID ID
+	// Err is the error that occurred for the item.
+	Err error
+}
+
+func (e BatchItemError) Error() string {
+	if e.ID != "" {
+		return fmt.Sprintf("item %d (%s): %v", e.Index, e.ID, e.Err)
+	}
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned by operations that act on many items at once when
+// some of the items fail.  The items that succeeded are still returned or
+// applied, so callers can inspect the failures and retry only those.
+//
+// [errors.Is] and [errors.As] match against any of the contained errors, so
+// for example errors.As(err, &spotifyErr) finds the first [Error] reported
+// for any item.
+type BatchError struct {
+	// Errors holds one entry per failed item, ordered by Index.
+	Errors []BatchItemError
+}
+
+func (e BatchError) Error() string {
+	switch len(e.Errors) {
+	case 0:
+		return "spotify: batch operation failed"
+	case 1:
+		return fmt.Sprintf("spotify: batch operation failed for %v", e.Errors[0])
+	default:
+		return fmt.Sprintf("spotify: batch operation failed for %d items, first %v", len(e.Errors), e.Errors[0])
+	}
+}
+
+// Unwrap returns the errors of the failed items.
+func (e BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, item := range e.Errors {
+		errs[i] = item
+	}
+	return errs
+}
+
+// Is reports whether any of the contained errors matches target.
+func (e BatchError) Is(target error) bool {
+	for _, item := range e.Errors {
+		if errors.Is(item, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first contained error that matches target, and if one is
+// found, sets target to that error value and returns true.
+func (e BatchError) As(target interface{}) bool {
+	for _, item := range e.Errors {
+		if errors.As(item, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// newBatchError builds a [BatchError] from the per-item errors of a batch
+// operation on ids, where errs[i] is the error for ids[i].  It returns nil if
+// none of the items failed.
+func newBatchError(ids []ID, errs []error) error {
+	var e BatchError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		item := BatchItemError{Index: i, Err: err}
+		if i < len(ids) {
+			item.ID = ids[i]
+		}
+		e.Errors = append(e.Errors, item)
+	}
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// forEachConcurrent calls fn once for each index in [0, n), running at most
+// limit calls at a time.  Unlike a typical error group, a failed call does not
+// stop the others; the result holds the error returned for each index (nil
+// for the calls that succeeded).  If ctx is canceled, the calls that have not
+// yet started report the context's error.
+func forEachConcurrent(ctx context.Context, n, limit int, fn func(ctx context.Context, i int) error) []error {
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
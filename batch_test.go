@@ -0,0 +1,86 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBatchErrorIsAs(t *testing.T) {
+	apiErr := Error{Message: "non existing id", Status: http.StatusNotFound}
+	err := newBatchError([]ID{"id0", "id1", "id2"}, []error{nil, context.Canceled, apiErr})
+
+	var be BatchError
+	if !errors.As(err, &be) {
+		t.Fatal("Expected a BatchError, got", err)
+	}
+	if len(be.Errors) != 2 || be.Errors[0].Index != 1 || be.Errors[1].ID != "id2" {
+		t.Errorf("Unexpected item errors: %v", be.Errors)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("Expected errors.Is to match a contained error")
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected errors.Is not to match an error that isn't contained")
+	}
+
+	var se Error
+	if !errors.As(err, &se) || se.Status != http.StatusNotFound {
+		t.Error("Expected errors.As to find the contained spotify error")
+	}
+	var item BatchItemError
+	if !errors.As(err, &item) || item.ID != "id1" {
+		t.Error("Expected errors.As to find the first item error")
+	}
+
+	expected := "spotify: batch operation failed for 2 items, first item 1 (id1): context canceled"
+	if msg := err.Error(); msg != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, msg)
+	}
+
+	if err := newBatchError([]ID{"id0"}, []error{nil}); err != nil {
+		t.Error("Expected no error when every item succeeded, got", err)
+	}
+}
+
+func TestWithoutAutoChunk(t *testing.T) {
+	requests := 0
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{ "snapshot_id": "snapshot" }`)
+	}))
+	defer server.Close()
+	WithoutAutoChunk()(client)
+
+	ids := make([]ID, 101)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", i))
+	}
+
+	_, err := client.AddTracksToPlaylist(context.Background(), "playlist_id", ids...)
+	var tooMany TooManyIDsError
+	if !errors.As(err, &tooMany) || tooMany != 100 {
+		t.Errorf("Expected TooManyIDsError(100), got %v", err)
+	}
+	_, err = client.GetArtists(context.Background(), ids[:51]...)
+	if !errors.As(err, &tooMany) || tooMany != 50 {
+		t.Errorf("Expected TooManyIDsError(50), got %v", err)
+	}
+	_, err = client.GetAlbums(context.Background(), ids[:21])
+	if !errors.As(err, &tooMany) || tooMany != 20 {
+		t.Errorf("Expected TooManyIDsError(20), got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+
+	if _, err := client.AddTracksToPlaylist(context.Background(), "playlist_id", ids[:100]...); err != nil {
+		t.Error(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request for 100 tracks, got %d", requests)
+	}
+}
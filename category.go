@@ -3,6 +3,7 @@ package spotify
 import (
 	"context"
 	"fmt"
+	"net/http"
 )
 
 // Category is used by Spotify to tag items in.  For example, on the Spotify
@@ -33,7 +34,27 @@ func (c *Client) GetCategory(ctx context.Context, id string, opts ...RequestOpti
 	return cat, err
 }
 
+// CategoryNotFoundError is returned by [Client.GetCategoryPlaylists] when
+// Spotify doesn't know the category, which happens as category IDs change
+// over time.  Use [Client.ListValidCategoryIDs] to find the current ones.
+type CategoryNotFoundError struct {
+	// ID is the ID of the category that wasn't found.
+	ID string
+	// Err is the [Error] returned by Spotify.
+	Err error
+}
+
+func (e CategoryNotFoundError) Error() string {
+	return fmt.Sprintf("spotify: category %q not found", e.ID)
+}
+
+// Unwrap returns the underlying error.
+func (e CategoryNotFoundError) Unwrap() error {
+	return e.Err
+}
+
 // GetCategoryPlaylists gets a list of Spotify playlists tagged with a particular category.
+// If Spotify doesn't know the category, a [CategoryNotFoundError] is returned.
 //
 // Supported options: [Country], [Limit], [Offset].
 func (c *Client) GetCategoryPlaylists(ctx context.Context, catID string, opts ...RequestOption) (*SimplePlaylistPage, error) {
@@ -48,6 +69,9 @@ func (c *Client) GetCategoryPlaylists(ctx context.Context, catID string, opts ..
 
 	err := c.get(ctx, spotifyURL, &wrapper)
 	if err != nil {
+		if e, ok := err.(Error); ok && e.Status == http.StatusNotFound {
+			return nil, CategoryNotFoundError{ID: catID, Err: err}
+		}
 		return nil, err
 	}
 
@@ -74,3 +98,26 @@ func (c *Client) GetCategories(ctx context.Context, opts ...RequestOption) (*Cat
 
 	return &wrapper.Categories, nil
 }
+
+// ListValidCategoryIDs returns the IDs of all of the categories that Spotify
+// currently knows, paging through the results of [Client.GetCategories].  It
+// can be used to check a category ID before calling
+// [Client.GetCategoryPlaylists].
+//
+// Supported options: [Country], [Locale].
+func (c *Client) ListValidCategoryIDs(ctx context.Context, opts ...RequestOption) ([]string, error) {
+	var ids []string
+	for {
+		pageOpts := append(opts[:len(opts):len(opts)], Limit(50), Offset(len(ids)))
+		page, err := c.GetCategories(ctx, pageOpts...)
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range page.Categories {
+			ids = append(ids, category.ID)
+		}
+		if len(page.Categories) == 0 || page.Next == "" || len(ids) >= int(page.Total) {
+			return ids, nil
+		}
+	}
+}
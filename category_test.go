@@ -2,7 +2,11 @@ package spotify
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -92,11 +96,55 @@ func TestGetCategoryPlaylistsOpt(t *testing.T) {
 	defer server.Close()
 
 	_, err := client.GetCategoryPlaylists(context.Background(), "id", Limit(5), Offset(10))
-	if want := "spotify: Not Found [404]"; err == nil || err.Error() != want {
+	if want := "spotify: category \"id\" not found"; err == nil || err.Error() != want {
 		t.Errorf("Expected error: want %v, got %v", want, err)
 	}
 }
 
+func TestGetCategoryPlaylistsNotFound(t *testing.T) {
+	client, server := testClientString(http.StatusNotFound, `{ "error": { "status": 404, "message": "Specified id doesn't exist" } }`)
+	defer server.Close()
+
+	_, err := client.GetCategoryPlaylists(context.Background(), "toplists")
+	var notFound CategoryNotFoundError
+	if !errors.As(err, &notFound) || notFound.ID != "toplists" {
+		t.Fatalf("Expected CategoryNotFoundError for toplists, got %v", err)
+	}
+	var spotifyErr Error
+	if !errors.As(err, &spotifyErr) || spotifyErr.Message != "Specified id doesn't exist" {
+		t.Errorf("Expected the underlying Spotify error, got %v", notFound.Err)
+	}
+}
+
+func TestListValidCategoryIDs(t *testing.T) {
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()
+		if l := values.Get("locale"); l != "es_MX" {
+			t.Errorf("Expected locale 'es_MX', got '%s'", l)
+		}
+		offset, _ := strconv.Atoi(values.Get("offset"))
+		var items []string
+		for i := offset; i < offset+50 && i < 60; i++ {
+			items = append(items, fmt.Sprintf(`{ "id": "category%d" }`, i))
+		}
+		next := `"next"`
+		if offset+50 >= 60 {
+			next = "null"
+		}
+		fmt.Fprintf(w, `{ "categories": { "items": [ %s ], "next": %s, "offset": %d, "total": 60 } }`,
+			strings.Join(items, ","), next, offset)
+	}))
+	defer server.Close()
+
+	ids, err := client.ListValidCategoryIDs(context.Background(), Locale("es_MX"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 60 || ids[0] != "category0" || ids[59] != "category59" {
+		t.Errorf("Expected 60 categories, got %v", ids)
+	}
+}
+
 func TestGetCategoriesInvalidToken(t *testing.T) {
 	client, server := testClientString(http.StatusUnauthorized, invalidToken)
 	defer server.Close()
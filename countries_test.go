@@ -0,0 +1,32 @@
+package spotify
+
+import "testing"
+
+func TestCountryName(t *testing.T) {
+	if name := CountryName("SE"); name != "Sweden" {
+		t.Errorf("Expected Sweden, got '%s'", name)
+	}
+	if name := CountryName("gb"); name != "United Kingdom" {
+		t.Errorf("Expected United Kingdom, got '%s'", name)
+	}
+	if name := CountryName("ZZ"); name != "" {
+		t.Errorf("Expected no name for an unknown code, got '%s'", name)
+	}
+	if code := CountryFromName("côte d'ivoire"); code != "CI" {
+		t.Errorf("Expected CI, got '%s'", code)
+	}
+	if code := CountryFromName("Atlantis"); code != "" {
+		t.Errorf("Expected no code for an unknown name, got '%s'", code)
+	}
+
+	for code, name := range countryNames {
+		if c := CountryFromName(name); c != code {
+			t.Errorf("%s: got code %s back for name %s", code, c, name)
+		}
+	}
+	for _, code := range []string{CountryUSA, CountryUnitedArabEmirates, CountryHongKong, CountryNewZealand} {
+		if CountryName(code) == "" {
+			t.Errorf("Expected a name for %s", code)
+		}
+	}
+}
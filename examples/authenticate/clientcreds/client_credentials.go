@@ -9,27 +9,15 @@ package main
 import (
 	"context"
 	"fmt"
-	"github.com/zmb3/spotify/v2/auth"
 	"log"
-	"os"
 
 	"github.com/zmb3/spotify/v2"
-	"golang.org/x/oauth2/clientcredentials"
+	"github.com/zmb3/spotify/v2/auth"
 )
 
 func main() {
 	ctx := context.Background()
-	config := &clientcredentials.Config{
-		ClientID:     os.Getenv("SPOTIFY_ID"),
-		ClientSecret: os.Getenv("SPOTIFY_SECRET"),
-		TokenURL:     spotifyauth.TokenURL,
-	}
-	token, err := config.Token(ctx)
-	if err != nil {
-		log.Fatalf("couldn't get token: %v", err)
-	}
-
-	httpClient := spotifyauth.New().Client(ctx, token)
+	httpClient := spotifyauth.New().ClientCredentialsClient(ctx)
 	client := spotify.New(httpClient)
 	msg, page, err := client.FeaturedPlaylists(ctx)
 	if err != nil {
@@ -9,14 +9,12 @@ package main
 import (
 	"context"
 	"fmt"
-	spotifyauth "github.com/zmb3/spotify/v2/auth"
 	"log"
-	"os"
 	"net/http"
-
-	"golang.org/x/oauth2"
+	"os"
 
 	"github.com/zmb3/spotify/v2"
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
 )
 
 // redirectURI is the OAuth redirect URI for the application.
@@ -25,14 +23,16 @@ import (
 const redirectURI = "http://localhost:8080/callback"
 
 var (
-	auth  = spotifyauth.New(spotifyauth.WithRedirectURL(redirectURI), spotifyauth.WithScopes(spotifyauth.ScopeUserReadPrivate))
+	auth = spotifyauth.New(
+		spotifyauth.WithClientID(os.Getenv("SPOTIFY_ID")),
+		spotifyauth.WithRedirectURL(redirectURI),
+		spotifyauth.WithScopes(spotifyauth.ScopeUserReadPrivate),
+	)
 	ch    = make(chan *spotify.Client)
 	state = "abc123"
-	// These should be randomly generated for each request
-	//  More information on generating these can be found here,
-	// https://www.oauth.com/playground/authorization-code-with-pkce.html
-	codeVerifier  = "w0HfYrKnG8AihqYHA9_XUPTIcqEXQvCQfOF2IitRgmlF43YWJ8dy2b49ZUwVUOR.YnvzVoTBL57BwIhM4ouSa~tdf0eE_OmiMC_ESCcVOe7maSLIk9IOdBhRstAxjCl7"
-	codeChallenge = "ZhZJzPQXYBMjH8FlGAdYK5AndohLzFfZT-8J7biT7ig"
+	// codeVerifier is generated for each authorization, and must be kept
+	// until the code is exchanged for a token
+	codeVerifier string
 )
 
 func main() {
@@ -43,11 +43,12 @@ func main() {
 	})
 	go http.ListenAndServe(":8080", nil)
 
-	url := auth.AuthURL(state,
-		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
-		oauth2.SetAuthURLParam("code_challenge", codeChallenge),
-		oauth2.SetAuthURLParam("client_id", os.Getenv("CLIENT_ID")),
-	)
+	verifier, challenge, err := spotifyauth.GenerateCodeVerifier()
+	if err != nil {
+		log.Fatal(err)
+	}
+	codeVerifier = verifier
+	url := auth.AuthURLWithPKCE(state, challenge)
 	fmt.Println("Please log in to Spotify by visiting the following page in your browser:", url)
 
 	// wait for auth to complete
@@ -62,8 +63,7 @@ func main() {
 }
 
 func completeAuth(w http.ResponseWriter, r *http.Request) {
-	tok, err := auth.Token(r.Context(), state, r,
-		oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	tok, err := auth.Token(r.Context(), state, r, spotifyauth.CodeVerifier(codeVerifier))
 	if err != nil {
 		http.Error(w, "Couldn't get token", http.StatusForbidden)
 		log.Fatal(err)
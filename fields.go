@@ -0,0 +1,117 @@
+package spotify
+
+import "strings"
+
+// FieldsBuilder composes a filter for the [Fields] option, so that nested
+// selections don't have to be written out with hand-balanced parentheses.
+// The zero value is an empty filter ready to use.  For example:
+//
+//	f := NewFields("next", "total").
+//		Nested("items", NewFields("added_at").
+//			Episode(NewFields("name").Nested("show", NewFields("name"))))
+//	c.GetPlaylistItems(ctx, id, Fields(f.String()))
+//
+// asks for "next,total,items(added_at,track(name,show(name)))".
+type FieldsBuilder struct {
+	entries []fieldsEntry
+}
+
+type fieldsEntry struct {
+	name string
+	sub  *FieldsBuilder
+}
+
+// NewFields returns a builder that selects the given fields.
+func NewFields(fields ...string) *FieldsBuilder {
+	return new(FieldsBuilder).Add(fields...)
+}
+
+// Add selects fields, which may use the dot notation or exclamation mark
+// prefix described for [Fields].  Fields that are already selected are
+// skipped, except that a field whose sub-fields were selected with
+// [FieldsBuilder.Nested] is widened to the whole field.
+func (b *FieldsBuilder) Add(fields ...string) *FieldsBuilder {
+	for _, field := range fields {
+		if i := b.find(field); i < 0 {
+			b.entries = append(b.entries, fieldsEntry{name: field})
+		} else {
+			b.entries[i].sub = nil
+		}
+	}
+	return b
+}
+
+// Nested selects fields of the object, or of each object of the array,
+// called name.  Selecting the same name more than once merges the
+// selections into a single group.  If sub is nil or empty, and nothing else
+// is selected under name, the whole of name is selected.  If the whole of
+// name was already selected with [FieldsBuilder.Add], it stays selected as a
+// whole and sub is ignored.
+func (b *FieldsBuilder) Nested(name string, sub *FieldsBuilder) *FieldsBuilder {
+	i := b.find(name)
+	if i < 0 {
+		b.entries = append(b.entries, fieldsEntry{name: name, sub: new(FieldsBuilder)})
+		i = len(b.entries) - 1
+	} else if b.entries[i].sub == nil {
+		return b
+	}
+	if sub == nil {
+		return b
+	}
+	for _, e := range sub.entries {
+		if e.sub != nil {
+			b.entries[i].sub.Nested(e.name, e.sub)
+		} else {
+			b.entries[i].sub.Add(e.name)
+		}
+	}
+	return b
+}
+
+// Track selects fields of the track of a playlist item.  It is the same as
+// Nested("track", sub).
+func (b *FieldsBuilder) Track(sub *FieldsBuilder) *FieldsBuilder {
+	return b.Nested("track", sub)
+}
+
+// Episode selects fields of the episode of a playlist item.  Spotify returns
+// episodes under the "track" key of a playlist item just like tracks, so this
+// is also the same as Nested("track", sub); Track and Episode can be combined
+// to select fields of both, which are merged into one group.
+//
+// Keep "type" or a field that only episodes have, such as "show", in the
+// selection so that the item can be told apart from a track; see
+// [PlaylistItemTrack].
+func (b *FieldsBuilder) Episode(sub *FieldsBuilder) *FieldsBuilder {
+	return b.Nested("track", sub)
+}
+
+// String returns the filter in the syntax expected by [Fields].
+func (b *FieldsBuilder) String() string {
+	var sb strings.Builder
+	b.write(&sb)
+	return sb.String()
+}
+
+func (b *FieldsBuilder) write(sb *strings.Builder) {
+	for i, e := range b.entries {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(e.name)
+		if e.sub != nil && len(e.sub.entries) > 0 {
+			sb.WriteByte('(')
+			e.sub.write(sb)
+			sb.WriteByte(')')
+		}
+	}
+}
+
+func (b *FieldsBuilder) find(name string) int {
+	for i, e := range b.entries {
+		if e.name == name {
+			return i
+		}
+	}
+	return -1
+}
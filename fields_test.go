@@ -0,0 +1,66 @@
+package spotify
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestFieldsBuilder(t *testing.T) {
+	testTable := []struct {
+		Fields   *FieldsBuilder
+		Expected string
+	}{
+		{NewFields(), ""},
+		{NewFields("description", "uri", "uri"), "description,uri"},
+		{NewFields().Nested("tracks.items", NewFields("added_at", "added_by.id")), "tracks.items(added_at,added_by.id)"},
+		{NewFields("name").Nested("tracks", nil).Nested("owner", NewFields()), "name,tracks,owner"},
+		{NewFields().Nested("tracks", nil).Nested("tracks", NewFields("total")), "tracks(total)"},
+		{NewFields("tracks").Nested("tracks", NewFields("total")), "tracks"},
+		{NewFields().Nested("tracks", NewFields("total")).Add("tracks"), "tracks"},
+		{NewFields().Nested("items", NewFields("track")).Nested("items", NewFields().Track(NewFields("name"))), "items(track)"},
+		{
+			NewFields("next").
+				Nested("items", NewFields().Track(NewFields("name").Nested("album", NewFields("!name")))).
+				Nested("items", NewFields().Episode(NewFields("name", "type"))),
+			"next,items(track(name,album(!name),type))",
+		},
+	}
+
+	for _, tt := range testTable {
+		if got := tt.Fields.String(); got != tt.Expected {
+			t.Errorf("Expected %q, got %q", tt.Expected, got)
+		}
+	}
+}
+
+func TestFieldsBuilderEpisodes(t *testing.T) {
+	fields := NewFields("total").
+		Nested("items", NewFields().Episode(NewFields("name").Nested("show", NewFields("name"))))
+	const expected = "total,items(track(name,show(name)))"
+
+	client, server := testClientString(http.StatusOK, `{
+		"items": [ { "track": { "name": "Episode 1", "show": { "name": "The Show" } } } ],
+		"total": 1
+	}`, func(r *http.Request) {
+		if got := r.URL.Query().Get("fields"); got != expected {
+			t.Errorf("Expected fields %q, got %q", expected, got)
+		}
+	})
+	defer server.Close()
+
+	items, err := client.GetPlaylistItems(context.Background(), "playlistID", Fields(fields.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(items.Items))
+	}
+	episode := items.Items[0].Track.Episode
+	if episode == nil || items.Items[0].Track.Track != nil {
+		t.Fatalf("Expected an episode, got %+v", items.Items[0].Track)
+	}
+	if episode.Name != "Episode 1" || episode.Show.Name != "The Show" {
+		t.Errorf("Expected Episode 1 of The Show, got %s of %s", episode.Name, episode.Show.Name)
+	}
+}
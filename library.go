@@ -10,35 +10,77 @@ import (
 
 // UserHasTracks checks if one or more tracks are saved to the current user's
 // "Your Music" library.
+//
+// The result has exactly one entry per ID, in the order in which the IDs were
+// specified, including any duplicates.  More than 50 IDs may be passed; they
+// are checked in batches of 50.
 func (c *Client) UserHasTracks(ctx context.Context, ids ...ID) ([]bool, error) {
 	return c.libraryContains(ctx, "tracks", ids...)
 }
 
 // UserHasAlbums checks if one or more albums are saved to the current user's
 // "Your Albums" library.
+//
+// The result has exactly one entry per ID, in the order in which the IDs were
+// specified, including any duplicates.  More than 50 IDs may be passed; they
+// are checked in batches of 50.
 func (c *Client) UserHasAlbums(ctx context.Context, ids ...ID) ([]bool, error) {
 	return c.libraryContains(ctx, "albums", ids...)
 }
 
 func (c *Client) libraryContains(ctx context.Context, typ string, ids ...ID) ([]bool, error) {
-	if l := len(ids); l == 0 || l > 50 {
-		return nil, errors.New("spotify: supports 1 to 50 IDs per call")
+	if len(ids) == 0 {
+		return nil, errors.New("spotify: at least one ID is required")
 	}
-	spotifyURL := fmt.Sprintf("%sme/%s/contains?ids=%s", c.baseURL, typ, strings.Join(toStringSlice(ids), ","))
 
-	var result []bool
-
-	err := c.get(ctx, spotifyURL, &result)
-	if err != nil {
+	if err := c.checkChunkSize(len(ids), 50); err != nil {
 		return nil, err
 	}
 
-	return result, err
+	result := make([]bool, 0, len(ids))
+	for _, chunk := range chunkIDs(ids, 50) {
+		spotifyURL := fmt.Sprintf("%sme/%s/contains?ids=%s", c.baseURL, typ, strings.Join(toStringSlice(chunk), ","))
+
+		var contains []bool
+
+		err := c.get(ctx, spotifyURL, &contains)
+		if err != nil {
+			return nil, err
+		}
+		if len(contains) != len(chunk) {
+			return nil, fmt.Errorf("spotify: expected %d results, got %d", len(chunk), len(contains))
+		}
+
+		result = append(result, contains...)
+	}
+
+	return result, nil
+}
+
+// UserHasShows checks if one or more shows are saved to the current user's
+// library.
+//
+// The result has exactly one entry per ID, in the order in which the IDs were
+// specified, including any duplicates.  More than 50 IDs may be passed; they
+// are checked in batches of 50.
+func (c *Client) UserHasShows(ctx context.Context, ids ...ID) ([]bool, error) {
+	return c.libraryContains(ctx, "shows", ids...)
+}
+
+// UserHasEpisodes checks if one or more episodes are saved to the current
+// user's library.
+//
+// The result has exactly one entry per ID, in the order in which the IDs were
+// specified, including any duplicates.  More than 50 IDs may be passed; they
+// are checked in batches of 50.
+func (c *Client) UserHasEpisodes(ctx context.Context, ids ...ID) ([]bool, error) {
+	return c.libraryContains(ctx, "episodes", ids...)
 }
 
 // AddTracksToLibrary saves one or more tracks to the current user's
 // "Your Music" library.  This call requires the [ScopeUserLibraryModify] scope.
-// A track can only be saved once; duplicate IDs are ignored.
+// A track can only be saved once; duplicate IDs are ignored.  More than 50 IDs
+// may be passed; they are saved in batches of 50.
 func (c *Client) AddTracksToLibrary(ctx context.Context, ids ...ID) error {
 	return c.modifyLibrary(ctx, "tracks", true, ids...)
 }
@@ -47,6 +89,7 @@ func (c *Client) AddTracksToLibrary(ctx context.Context, ids ...ID) error {
 // "Your Music" library.  This call requires the [ScopeUserModifyLibrary] scope.
 // Trying to remove a track when you do not have the user's authorization
 // results in an [Error] with the status code set to [net/http.StatusUnauthorized].
+// More than 50 IDs may be passed; they are removed in batches of 50.
 func (c *Client) RemoveTracksFromLibrary(ctx context.Context, ids ...ID) error {
 	return c.modifyLibrary(ctx, "tracks", false, ids...)
 }
@@ -66,18 +109,30 @@ func (c *Client) RemoveAlbumsFromLibrary(ctx context.Context, ids ...ID) error {
 	return c.modifyLibrary(ctx, "albums", false, ids...)
 }
 
+// modifyLibrary saves or removes items in batches of 50, the most that
+// Spotify accepts in a single request.  If a batch fails, the batches before it
+// have already been applied.
 func (c *Client) modifyLibrary(ctx context.Context, typ string, add bool, ids ...ID) error {
-	if l := len(ids); l == 0 || l > 50 {
-		return errors.New("spotify: this call supports 1 to 50 IDs per call")
+	if len(ids) == 0 {
+		return errors.New("spotify: at least one ID is required")
 	}
-	spotifyURL := fmt.Sprintf("%sme/%s?ids=%s", c.baseURL, typ, strings.Join(toStringSlice(ids), ","))
+	if err := c.checkChunkSize(len(ids), 50); err != nil {
+		return err
+	}
+
 	method := "DELETE"
 	if add {
 		method = "PUT"
 	}
-	req, err := http.NewRequestWithContext(ctx, method, spotifyURL, nil)
-	if err != nil {
-		return err
+	for _, chunk := range chunkIDs(ids, 50) {
+		spotifyURL := fmt.Sprintf("%sme/%s?ids=%s", c.baseURL, typ, strings.Join(toStringSlice(chunk), ","))
+		req, err := http.NewRequestWithContext(ctx, method, spotifyURL, nil)
+		if err != nil {
+			return err
+		}
+		if err := c.execute(req, nil); err != nil {
+			return err
+		}
 	}
-	return c.execute(req, nil)
+	return nil
 }
@@ -2,8 +2,14 @@ package spotify
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -23,6 +29,48 @@ func TestUserHasTracks(t *testing.T) {
 	}
 }
 
+func TestUserHasTracksDuplicateIDs(t *testing.T) {
+	saved := map[string]bool{"saved": true}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		if len(ids) > 50 {
+			t.Errorf("Expected at most 50 IDs per request, got %d", len(ids))
+		}
+		result := make([]bool, len(ids))
+		for i, id := range ids {
+			result[i] = saved[id]
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+	defer server.Close()
+	client := &Client{http: http.DefaultClient, baseURL: server.URL + "/"}
+
+	var ids []ID
+	for i := 0; i < 60; i++ {
+		ids = append(ids, ID(fmt.Sprintf("track%d", i)))
+	}
+	// repeat a saved track on both sides of the chunk boundary
+	ids[3], ids[49], ids[50], ids[59] = "saved", "saved", "saved", "saved"
+
+	contains, err := client.UserHasTracks(context.Background(), ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if l := len(contains); l != len(ids) {
+		t.Fatalf("Expected %d results, got %d", len(ids), l)
+	}
+	for i, id := range ids {
+		if contains[i] != (id == "saved") {
+			t.Errorf("Result %d (%s) misaligned: got %t", i, id, contains[i])
+		}
+	}
+}
+
 func TestAddTracksToLibrary(t *testing.T) {
 	client, server := testClientString(http.StatusOK, "")
 	defer server.Close()
@@ -71,6 +119,43 @@ func TestRemoveTracksFromLibrary(t *testing.T) {
 	}
 }
 
+func TestModifyLibraryInChunks(t *testing.T) {
+	var methods []string
+	var batches [][]string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		batches = append(batches, strings.Split(r.URL.Query().Get("ids"), ","))
+	}))
+	defer server.Close()
+
+	ids := make([]ID, 120)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", i))
+	}
+
+	if err := client.AddTracksToLibrary(context.Background(), ids...); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 3 || len(batches[0]) != 50 || len(batches[1]) != 50 || len(batches[2]) != 20 {
+		t.Fatalf("Expected batches of 50, 50 and 20 tracks, got %d batches", len(batches))
+	}
+	if batches[2][19] != "track119" {
+		t.Errorf("Expected the last batch to end with track119, got %s", batches[2][19])
+	}
+
+	methods, batches = nil, nil
+	if err := client.RemoveTracksFromLibrary(context.Background(), ids[:51]...); err != nil {
+		t.Fatal(err)
+	}
+	if len(methods) != 2 || methods[0] != "DELETE" || methods[1] != "DELETE" {
+		t.Errorf("Expected 2 DELETE requests, got %v", methods)
+	}
+
+	if err := client.AddTracksToLibrary(context.Background()); err == nil {
+		t.Error("Expected an error without IDs")
+	}
+}
+
 func TestUserHasAlbums(t *testing.T) {
 	client, server := testClientString(http.StatusOK, `[ false, true ]`)
 	defer server.Close()
@@ -121,3 +206,95 @@ func TestRemoveAlbumsFromLibrary(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestShowsAndEpisodesLibrary(t *testing.T) {
+	var requests []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		requests = append(requests, fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, len(ids)))
+		if strings.HasSuffix(r.URL.Path, "/contains") {
+			contains := make([]string, len(ids))
+			for i := range ids {
+				contains[i] = strconv.FormatBool(i%2 == 0)
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(contains, ","))
+		}
+	}))
+	defer server.Close()
+
+	ids := make([]ID, 55)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("id%d", i))
+	}
+	ctx := context.Background()
+
+	if err := client.SaveShowsForCurrentUser(ctx, ids); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveShowsForCurrentUser(ctx, ids[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SaveEpisodesForCurrentUser(ctx, ids[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RemoveEpisodesForCurrentUser(ctx, ids); err != nil {
+		t.Fatal(err)
+	}
+	shows, err := client.UserHasShows(ctx, ids[:3]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	episodes, err := client.UserHasEpisodes(ctx, ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /me/shows 50",
+		"PUT /me/shows 5",
+		"DELETE /me/shows 2",
+		"PUT /me/episodes 2",
+		"DELETE /me/episodes 50",
+		"DELETE /me/episodes 5",
+		"GET /me/shows/contains 3",
+		"GET /me/episodes/contains 50",
+		"GET /me/episodes/contains 5",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+	if !reflect.DeepEqual(shows, []bool{true, false, true}) {
+		t.Errorf("Wrong result for shows: %v", shows)
+	}
+	if len(episodes) != len(ids) || !episodes[50] || episodes[51] {
+		t.Errorf("Wrong result for episodes: %v", episodes)
+	}
+}
+
+func TestCurrentUsersEpisodes(t *testing.T) {
+	client, server := testClientString(http.StatusOK, `{
+		"items": [ {
+			"added_at": "2023-05-01T10:00:00Z",
+			"episode": { "id": "512ojhOuo1ktJprKbVcKyQ", "name": "Episode 1", "show": { "name": "The Show" } }
+		} ],
+		"limit": 20,
+		"total": 1
+	}`, func(r *http.Request) {
+		if r.URL.Path != "/me/episodes" || r.URL.Query().Get("market") != CountryUnitedKingdom {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	})
+	defer server.Close()
+
+	episodes, err := client.CurrentUsersEpisodes(context.Background(), Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(episodes.Episodes) != 1 {
+		t.Fatalf("Expected 1 episode, got %d", len(episodes.Episodes))
+	}
+	episode := episodes.Episodes[0]
+	if episode.AddedAt != "2023-05-01T10:00:00Z" || episode.Name != "Episode 1" || episode.Show.Name != "The Show" {
+		t.Errorf("Unexpected episode %+v", episode)
+	}
+}
@@ -0,0 +1,107 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// shareKinds lists the kinds of objects that [Client.GetFromURL] can fetch.
+var shareKinds = map[string]bool{
+	"track":    true,
+	"album":    true,
+	"artist":   true,
+	"playlist": true,
+	"show":     true,
+	"episode":  true,
+}
+
+// ParseShareURL parses a link to a track, album, artist, playlist, show or
+// episode, as copied from a Spotify app, and returns the kind of object that
+// it links to, such as "track", and the object's ID.  Both open.spotify.com
+// links, such as "https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6?si=x",
+// and Spotify URIs, such as "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", are
+// accepted.  Localized ("/intl-de/track/...") and embed links, and the legacy
+// "/user/{user_id}/playlist/{playlist_id}" form, are understood as well.
+func ParseShareURL(shareURL string) (kind string, id ID, err error) {
+	shareURL = strings.TrimSpace(shareURL)
+
+	var parts []string
+	if strings.HasPrefix(shareURL, "spotify:") {
+		parts = strings.Split(strings.TrimPrefix(shareURL, "spotify:"), ":")
+	} else {
+		u, err := url.Parse(shareURL)
+		if err != nil {
+			return "", "", fmt.Errorf("spotify: invalid share URL: %w", err)
+		}
+		if host := strings.ToLower(u.Hostname()); host != "open.spotify.com" && host != "play.spotify.com" {
+			return "", "", fmt.Errorf("spotify: %q is not a Spotify share URL", shareURL)
+		}
+		parts = strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(parts) > 0 && strings.HasPrefix(parts[0], "intl-") {
+			parts = parts[1:]
+		}
+		if len(parts) > 0 && parts[0] == "embed" {
+			parts = parts[1:]
+		}
+	}
+	// legacy playlist links include the owner's user ID
+	if len(parts) == 4 && parts[0] == "user" && parts[2] == "playlist" {
+		parts = parts[2:]
+	}
+
+	if len(parts) != 2 || !shareKinds[parts[0]] || !isBase62(parts[1]) {
+		return "", "", fmt.Errorf("spotify: %q does not link to a track, album, artist, playlist, show or episode", shareURL)
+	}
+	return parts[0], ID(parts[1]), nil
+}
+
+// isBase62 reports whether s is a non-empty string of the characters used by
+// Spotify IDs.
+func isBase62(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// GetFromURL resolves a link copied from a Spotify app, in any form accepted
+// by [ParseShareURL], to the object that it links to.  It returns the kind of
+// the object along with the object itself, which is a [*FullTrack],
+// [*FullAlbum], [*FullArtist], [*FullPlaylist], [*FullShow] or [*EpisodePage]
+// for the kinds "track", "album", "artist", "playlist", "show" and "episode"
+// respectively.
+//
+// The options are passed on to the method that fetches the object, except for
+// artists, which don't support any.
+func (c *Client) GetFromURL(ctx context.Context, shareURL string, opts ...RequestOption) (kind string, object interface{}, err error) {
+	kind, id, err := ParseShareURL(shareURL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch kind {
+	case "track":
+		object, err = c.GetTrack(ctx, id, opts...)
+	case "album":
+		object, err = c.GetAlbum(ctx, id, opts...)
+	case "artist":
+		object, err = c.GetArtist(ctx, id)
+	case "playlist":
+		object, err = c.GetPlaylist(ctx, id, opts...)
+	case "show":
+		object, err = c.GetShow(ctx, id, opts...)
+	case "episode":
+		object, err = c.GetEpisode(ctx, string(id), opts...)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return kind, object, nil
+}
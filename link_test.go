@@ -0,0 +1,81 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestParseShareURL(t *testing.T) {
+	testTable := []struct {
+		URL  string
+		Kind string
+		ID   ID
+	}{
+		{"https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6", "track", "6rqhFgbbKwnb9MLmUQDhG6"},
+		{"https://open.spotify.com/album/0sNOF9WDwhWunNAHPD3Baj?si=abc123", "album", "0sNOF9WDwhWunNAHPD3Baj"},
+		{"https://open.spotify.com/intl-de/artist/0TnOYISbd1XYRBk9myaseg", "artist", "0TnOYISbd1XYRBk9myaseg"},
+		{"https://open.spotify.com/embed/playlist/37i9dQZF1DXcBWIGoYBM5M", "playlist", "37i9dQZF1DXcBWIGoYBM5M"},
+		{"http://open.spotify.com/user/spotify/playlist/37i9dQZF1DXcBWIGoYBM5M", "playlist", "37i9dQZF1DXcBWIGoYBM5M"},
+		{" https://open.spotify.com/show/5CfCWKI5pZ28U0uOzXkDHe/ ", "show", "5CfCWKI5pZ28U0uOzXkDHe"},
+		{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "episode", "512ojhOuo1ktJprKbVcKyQ"},
+		{"https://example.com/track/6rqhFgbbKwnb9MLmUQDhG6", "", ""},
+		{"https://open.spotify.com/genre/rock", "", ""},
+		{"https://open.spotify.com/track/", "", ""},
+		{"https://open.spotify.com/track/6rqhFgbb-KwnB", "", ""},
+		{"spotify:user:spotify", "", ""},
+	}
+
+	for _, tt := range testTable {
+		kind, id, err := ParseShareURL(tt.URL)
+		if tt.Kind == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s %s", tt.URL, kind, id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.URL, err)
+			continue
+		}
+		if kind != tt.Kind || id != tt.ID {
+			t.Errorf("%s: expected %s %s, got %s %s", tt.URL, tt.Kind, tt.ID, kind, id)
+		}
+	}
+}
+
+func TestGetFromURL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tracks/6rqhFgbbKwnb9MLmUQDhG6", func(w http.ResponseWriter, r *http.Request) {
+		if market := r.URL.Query().Get("market"); market != CountryUnitedKingdom {
+			t.Errorf("Expected market %s, got '%s'", CountryUnitedKingdom, market)
+		}
+		fmt.Fprint(w, `{ "id": "6rqhFgbbKwnb9MLmUQDhG6", "name": "Purple Haze" }`)
+	})
+	mux.HandleFunc("/episodes/512ojhOuo1ktJprKbVcKyQ", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "id": "512ojhOuo1ktJprKbVcKyQ", "name": "Episode 1" }`)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+
+	kind, object, err := client.GetFromURL(context.Background(), "https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6?si=abc123", Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if track, ok := object.(*FullTrack); kind != "track" || !ok || track.Name != "Purple Haze" {
+		t.Errorf("Expected the track, got %s %#v", kind, object)
+	}
+
+	kind, object, err = client.GetFromURL(context.Background(), "spotify:episode:512ojhOuo1ktJprKbVcKyQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if episode, ok := object.(*EpisodePage); kind != "episode" || !ok || episode.Name != "Episode 1" {
+		t.Errorf("Expected the episode, got %s %#v", kind, object)
+	}
+
+	if _, _, err := client.GetFromURL(context.Background(), "https://open.spotify.com/album/missing"); err == nil {
+		t.Error("Expected an error for a missing album")
+	}
+}
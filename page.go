@@ -1,10 +1,14 @@
 package spotify
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
+	"strings"
 )
 
 // ErrNoMorePages is the error returned when you attempt to get the next
@@ -59,6 +63,12 @@ type SavedShowPage struct {
 	Shows []SavedShow `json:"items"`
 }
 
+// SavedEpisodePage contains [SavedEpisodes] returned by the Web API.
+type SavedEpisodePage struct {
+	basePage
+	Episodes []SavedEpisode `json:"items"`
+}
+
 // SimplePlaylistPage contains [SimplePlaylists] returned by the Web API.
 type SimplePlaylistPage struct {
 	basePage
@@ -115,6 +125,11 @@ func (b *basePage) canPage() {}
 
 // NextPage fetches the next page of items and writes them into p.
 // It returns [ErrNoMorePages] if p already contains the last page.
+// Endpoints such as [Client.GetCategories] and [Client.NewReleases] wrap their
+// pages in an object with a single key, like {"categories": {...}}; such
+// pages are unwrapped, so NextPage works for them too.
+// A relative next URL is resolved against the client's base URL, and an
+// error is returned, leaving p unchanged, if the URL points to another host.
 func (c *Client) NextPage(ctx context.Context, p pageable) error {
 	if p == nil || reflect.ValueOf(p).IsNil() {
 		return fmt.Errorf("spotify: p must be a non-nil pointer to a page")
@@ -127,6 +142,10 @@ func (c *Client) NextPage(ctx context.Context, p pageable) error {
 	if len(nextURL) == 0 {
 		return ErrNoMorePages
 	}
+	nextURL, err := c.pageURL(nextURL)
+	if err != nil {
+		return err
+	}
 
 	// Zero out the page so that we can overwrite it in the next
 	// call to get. This is necessary because encoding/json does
@@ -134,11 +153,12 @@ func (c *Client) NextPage(ctx context.Context, p pageable) error {
 	zero := reflect.Zero(val.Type())
 	val.Set(zero)
 
-	return c.get(ctx, nextURL, p)
+	return c.getPage(ctx, nextURL, p)
 }
 
 // PreviousPage fetches the previous page of items and writes them into p.
-// It returns [ErrNoMorePages] if p already contains the last page.
+// It returns [ErrNoMorePages] if p already contains the first page.  The
+// previous URL is checked like the next URL is by [Client.NextPage].
 func (c *Client) PreviousPage(ctx context.Context, p pageable) error {
 	if p == nil || reflect.ValueOf(p).IsNil() {
 		return fmt.Errorf("spotify: p must be a non-nil pointer to a page")
@@ -151,6 +171,10 @@ func (c *Client) PreviousPage(ctx context.Context, p pageable) error {
 	if len(prevURL) == 0 {
 		return ErrNoMorePages
 	}
+	prevURL, err := c.pageURL(prevURL)
+	if err != nil {
+		return err
+	}
 
 	// Zero out the page so that we can overwrite it in the next
 	// call to get. This is necessary because encoding/json does
@@ -158,5 +182,83 @@ func (c *Client) PreviousPage(ctx context.Context, p pageable) error {
 	zero := reflect.Zero(val.Type())
 	val.Set(zero)
 
-	return c.get(ctx, prevURL, p)
+	return c.getPage(ctx, prevURL, p)
+}
+
+// pageWrapperKeys are the keys under which some endpoints wrap the page they
+// return, such as {"categories": {...}}.
+var pageWrapperKeys = map[string]bool{
+	"albums":     true,
+	"artists":    true,
+	"tracks":     true,
+	"playlists":  true,
+	"shows":      true,
+	"episodes":   true,
+	"audiobooks": true,
+	"categories": true,
+}
+
+// getPage fetches the page at url into p, unwrapping it if it is the only
+// field of an object under one of pageWrapperKeys, as some endpoints return it.
+func (c *Client) getPage(ctx context.Context, url string, p pageable) error {
+	var raw json.RawMessage
+	if err := c.get(ctx, url, &raw); err != nil {
+		return err
+	}
+	if inner, ok := unwrapPage(raw); ok {
+		raw = inner
+	}
+	return json.Unmarshal(raw, p)
+}
+
+// unwrapPage returns the page wrapped in raw, and whether raw is a wrapped
+// page.  Only the first key is read when it isn't, so that a plain page is
+// decoded just once.
+func unwrapPage(raw json.RawMessage) (json.RawMessage, bool) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+	if tok, err := dec.Token(); err != nil || !pageWrapperKeys[fmt.Sprint(tok)] {
+		return nil, false
+	}
+	var inner json.RawMessage
+	if err := dec.Decode(&inner); err != nil || !bytes.HasPrefix(inner, []byte("{")) {
+		return nil, false
+	}
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('}') {
+		return nil, false
+	}
+	return inner, true
+}
+
+// pageURL resolves link, the URL of another page of results, against the
+// client's base URL.  Spotify returns absolute URLs, but relative ones are
+// accepted too.  The resolved URL must be on the host of the base URL or of
+// the Spotify Web API, so that a page with a tampered link can't make the
+// client send its requests, and credentials, to another host.  When the
+// client has a base URL other than the Spotify Web API's, set with
+// [WithBaseURL], links to the Spotify Web API are rewritten to use it, so
+// that paging goes through the same proxy or mock as every other request.
+func (c *Client) pageURL(link string) (string, error) {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("spotify: invalid page URL: %w", err)
+	}
+
+	resolved := base.ResolveReference(ref)
+	if c.baseURL != defaultBaseURL && strings.HasPrefix(resolved.String(), defaultBaseURL) {
+		return c.baseURL + strings.TrimPrefix(resolved.String(), defaultBaseURL), nil
+	}
+	for _, allowed := range []string{c.baseURL, defaultBaseURL} {
+		u, err := url.Parse(allowed)
+		if err == nil && resolved.Scheme == u.Scheme && resolved.Host == u.Host {
+			return resolved.String(), nil
+		}
+	}
+	return "", fmt.Errorf("spotify: refusing to follow page URL to host %q", resolved.Host)
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -117,3 +118,99 @@ func TestClient_PreviousPage(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_NextPageURL(t *testing.T) {
+	testTable := []struct {
+		Name         string
+		Next         string
+		ExpectedPath string
+		Err          string
+	}{
+		{
+			"absolute",
+			"{server}/albums/0sNOF9WDwhWunNAHPD3Baj/tracks?offset=50&limit=50",
+			"/albums/0sNOF9WDwhWunNAHPD3Baj/tracks?offset=50&limit=50",
+			"",
+		},
+		{
+			"relative",
+			"albums/0sNOF9WDwhWunNAHPD3Baj/tracks?offset=50&limit=50",
+			"/albums/0sNOF9WDwhWunNAHPD3Baj/tracks?offset=50&limit=50",
+			"",
+		},
+		{
+			"relative to root",
+			"/v1/albums/0sNOF9WDwhWunNAHPD3Baj/tracks",
+			"/v1/albums/0sNOF9WDwhWunNAHPD3Baj/tracks",
+			"",
+		},
+		{
+			"spotify rewritten to the base URL",
+			"https://api.spotify.com/v1/albums/0sNOF9WDwhWunNAHPD3Baj/tracks?offset=50&limit=50",
+			"/albums/0sNOF9WDwhWunNAHPD3Baj/tracks?offset=50&limit=50",
+			"",
+		},
+		{
+			"off host",
+			"https://evil.example.com/albums/0sNOF9WDwhWunNAHPD3Baj/tracks",
+			"",
+			`spotify: refusing to follow page URL to host "evil.example.com"`,
+		},
+		{
+			"protocol relative off host",
+			"//evil.example.com/albums/0sNOF9WDwhWunNAHPD3Baj/tracks",
+			"",
+			`spotify: refusing to follow page URL to host "evil.example.com"`,
+		},
+	}
+
+	for _, tt := range testTable {
+		t.Run(tt.Name, func(t *testing.T) {
+			wasCalled := false
+			client, server := testClientString(200, `{"total": 100}`, func(request *http.Request) {
+				wasCalled = true
+				assert.Equal(t, tt.ExpectedPath, request.URL.RequestURI())
+			})
+			defer server.Close()
+
+			page := &basePage{
+				Next:  strings.Replace(tt.Next, "{server}", server.URL, 1),
+				Total: 600,
+			}
+			err := client.NextPage(context.Background(), page)
+			assert.Equal(t, tt.ExpectedPath != "", wasCalled)
+			if tt.Err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, 100, int(page.Total))
+			} else {
+				assert.EqualError(t, err, tt.Err)
+				assert.Equal(t, 600, int(page.Total)) // the page is left untouched
+			}
+		})
+	}
+}
+
+func TestClient_NextPageWrapped(t *testing.T) {
+	client, server := testClientString(200, `{ "categories": { "items": [ { "id": "mood" } ], "offset": 2, "total": 3 } }`)
+	defer server.Close()
+
+	page := &CategoryPage{}
+	page.Next = server.URL + "/browse/categories?offset=2&limit=2"
+	err := client.NextPage(context.Background(), page)
+	assert.NoError(t, err)
+	if assert.Len(t, page.Categories, 1) {
+		assert.Equal(t, "mood", page.Categories[0].ID)
+	}
+	assert.Equal(t, 2, int(page.Offset))
+}
+
+func TestClient_NextPageNotWrapped(t *testing.T) {
+	client, server := testClientString(200, `{ "categorie": { "items": [ { "id": "mood" } ], "offset": 2, "total": 3 } }`)
+	defer server.Close()
+
+	page := &CategoryPage{}
+	page.Next = server.URL + "/browse/categories?offset=2&limit=2"
+	err := client.NextPage(context.Background(), page)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Categories)
+}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,6 +19,8 @@ type PlayerDevice struct {
 	ID ID `json:"id"`
 	// Active If this device is the currently active device.
 	Active bool `json:"is_active"`
+	// PrivateSession If this device is currently in a private session.
+	PrivateSession bool `json:"is_private_session"`
 	// Restricted Whether controlling this device is restricted. At present if
 	// this is "true" then no Web API commands will be accepted by this device.
 	Restricted bool `json:"is_restricted"`
@@ -27,6 +32,64 @@ type PlayerDevice struct {
 	Volume Numeric `json:"volume_percent"`
 }
 
+// PlayerErrorReason explains why a player command failed.  It is reported in
+// the Reason field of an [Error], so that, for example, a missing device can
+// be told apart from an account that isn't Premium:
+//
+//	var e spotify.Error
+//	if errors.As(err, &e) && e.Reason == spotify.ReasonNoActiveDevice {
+//		// ask the user to pick a device
+//	}
+type PlayerErrorReason string
+
+// The reasons that Spotify reports for failed player commands.
+const (
+	// ReasonNoPrevTrack means there is no previous track to skip to.
+	ReasonNoPrevTrack PlayerErrorReason = "NO_PREV_TRACK"
+	// ReasonNoNextTrack means there is no next track to skip to.
+	ReasonNoNextTrack PlayerErrorReason = "NO_NEXT_TRACK"
+	// ReasonNoSpecificTrack means the requested track doesn't exist.
+	ReasonNoSpecificTrack PlayerErrorReason = "NO_SPECIFIC_TRACK"
+	// ReasonAlreadyPaused means playback is already paused.
+	ReasonAlreadyPaused PlayerErrorReason = "ALREADY_PAUSED"
+	// ReasonNotPaused means playback isn't paused, so it can't be resumed.
+	ReasonNotPaused PlayerErrorReason = "NOT_PAUSED"
+	// ReasonNotPlayingLocally means the command can't be applied to a
+	// remote device.
+	ReasonNotPlayingLocally PlayerErrorReason = "NOT_PLAYING_LOCALLY"
+	// ReasonNotPlayingTrack means no track is playing.
+	ReasonNotPlayingTrack PlayerErrorReason = "NOT_PLAYING_TRACK"
+	// ReasonNotPlayingContext means no context is playing.
+	ReasonNotPlayingContext PlayerErrorReason = "NOT_PLAYING_CONTEXT"
+	// ReasonEndlessContext means the playing context, such as a radio
+	// station, can't be shuffled or repeated.
+	ReasonEndlessContext PlayerErrorReason = "ENDLESS_CONTEXT"
+	// ReasonContextDisallow means the playing context doesn't allow the
+	// command.  See [PlayerDisallows].
+	ReasonContextDisallow PlayerErrorReason = "CONTEXT_DISALLOW"
+	// ReasonAlreadyPlaying means the track is already playing.
+	ReasonAlreadyPlaying PlayerErrorReason = "ALREADY_PLAYING"
+	// ReasonRateLimited means too many player commands were sent too
+	// quickly.
+	ReasonRateLimited PlayerErrorReason = "RATE_LIMITED"
+	// ReasonRemoteControlDisallow means the device can't be controlled
+	// remotely.
+	ReasonRemoteControlDisallow PlayerErrorReason = "REMOTE_CONTROL_DISALLOW"
+	// ReasonDeviceNotControllable means the device can't be controlled.
+	ReasonDeviceNotControllable PlayerErrorReason = "DEVICE_NOT_CONTROLLABLE"
+	// ReasonVolumeControlDisallow means the device's volume can't be
+	// changed.
+	ReasonVolumeControlDisallow PlayerErrorReason = "VOLUME_CONTROL_DISALLOW"
+	// ReasonNoActiveDevice means there is no active device to play on, so
+	// one has to be chosen, for example with [Client.TransferPlayback].
+	ReasonNoActiveDevice PlayerErrorReason = "NO_ACTIVE_DEVICE"
+	// ReasonPremiumRequired means the command needs a Spotify Premium
+	// account.
+	ReasonPremiumRequired PlayerErrorReason = "PREMIUM_REQUIRED"
+	// ReasonUnknown means the command failed for some other reason.
+	ReasonUnknown PlayerErrorReason = "UNKNOWN"
+)
+
 // PlayerState contains information about the current playback.
 type PlayerState struct {
 	CurrentlyPlaying
@@ -38,6 +101,26 @@ type PlayerState struct {
 	RepeatState string `json:"repeat_state"`
 }
 
+// UnmarshalJSON decodes the playback state.  It is needed because the
+// embedded [CurrentlyPlaying] has its own UnmarshalJSON method, which would
+// otherwise be used for the whole state and ignore the fields above.
+func (ps *PlayerState) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &ps.CurrentlyPlaying); err != nil {
+		return err
+	}
+
+	var state struct {
+		Device       PlayerDevice `json:"device"`
+		ShuffleState bool         `json:"shuffle_state"`
+		RepeatState  string       `json:"repeat_state"`
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return err
+	}
+	ps.Device, ps.ShuffleState, ps.RepeatState = state.Device, state.ShuffleState, state.RepeatState
+	return nil
+}
+
 // PlaybackContext is the playback context.
 type PlaybackContext struct {
 	// ExternalURLs of the context, or null if not available.
@@ -60,10 +143,120 @@ type CurrentlyPlaying struct {
 	Progress Numeric `json:"progress_ms"`
 	// Playing If something is currently playing.
 	Playing bool `json:"is_playing"`
-	// The currently playing track. Can be null.
+	// CurrentlyPlayingType is the type of the currently playing item: one of
+	// "track", "episode", "ad" or "unknown".
+	CurrentlyPlayingType string `json:"currently_playing_type"`
+	// The currently playing track. Can be null.  When the request specifies a
+	// [Market] and Spotify relinked the track, Item describes the track that
+	// is actually playing and Item.LinkedFrom identifies the track that was
+	// originally requested, such as the one in the playlist being played.
 	Item *FullTrack `json:"item"`
+	// Episode is the currently playing episode, if CurrentlyPlayingType is
+	// "episode", in which case Item is nil.  Spotify only reports episodes
+	// when the request specifies [AdditionalTypes] including
+	// [EpisodeAdditionalType].
+	Episode *EpisodePage `json:"-"`
+	// Actions describes which playback controls are currently disallowed,
+	// so that they can be disabled instead of failing when used.
+	Actions PlayerActions `json:"actions"`
+}
+
+// PlayerActions describes the actions allowed in the current playback
+// context.
+type PlayerActions struct {
+	Disallows PlayerDisallows `json:"disallows"`
+}
+
+// PlayerDisallows reports the actions that are disallowed in the current
+// playback context, for example skipping to the next track while an ad is
+// playing.  Spotify only reports the disallowed actions, so each field is
+// true if the action is disallowed and false otherwise.  Attempting a
+// disallowed action typically fails with a 403 error.
+type PlayerDisallows struct {
+	InterruptingPlayback  bool `json:"interrupting_playback"`
+	Pausing               bool `json:"pausing"`
+	Resuming              bool `json:"resuming"`
+	Seeking               bool `json:"seeking"`
+	SkippingNext          bool `json:"skipping_next"`
+	SkippingPrev          bool `json:"skipping_prev"`
+	TogglingRepeatContext bool `json:"toggling_repeat_context"`
+	TogglingShuffle       bool `json:"toggling_shuffle"`
+	TogglingRepeatTrack   bool `json:"toggling_repeat_track"`
+	TransferringPlayback  bool `json:"transferring_playback"`
+}
+
+// UnmarshalJSON decodes the currently playing item into Item or Episode,
+// according to its type.
+func (cp *CurrentlyPlaying) UnmarshalJSON(b []byte) error {
+	// currentlyPlaying has the fields of CurrentlyPlaying, but not this
+	// method, so it can be decoded without recursing.
+	type currentlyPlaying CurrentlyPlaying
+	var v struct {
+		currentlyPlaying
+		Item json.RawMessage `json:"item"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*cp = CurrentlyPlaying(v.currentlyPlaying)
+
+	if len(v.Item) == 0 || bytes.Equal(v.Item, []byte("null")) {
+		return nil
+	}
+	switch v.CurrentlyPlayingType {
+	case "episode":
+		return json.Unmarshal(v.Item, &cp.Episode)
+	case "track", "":
+		return json.Unmarshal(v.Item, &cp.Item)
+	default:
+		return nil
+	}
 }
 
+// itemDuration returns the duration of the currently playing track or episode,
+// and false if nothing is playing.
+func (cp *CurrentlyPlaying) itemDuration() (Numeric, bool) {
+	switch {
+	case cp == nil:
+		return 0, false
+	case cp.Item != nil:
+		return cp.Item.Duration, true
+	case cp.Episode != nil:
+		return cp.Episode.Duration_ms, true
+	default:
+		return 0, false
+	}
+}
+
+// ProgressDuration returns the progress into the currently playing track or
+// episode as a [time.Duration].  It returns zero if nothing is playing.  The
+// Progress field can't be used for this method's name, hence the suffix.
+func (cp *CurrentlyPlaying) ProgressDuration() time.Duration {
+	if _, ok := cp.itemDuration(); !ok {
+		return 0
+	}
+	return time.Duration(cp.Progress) * time.Millisecond
+}
+
+// Remaining returns the time left until the end of the currently playing
+// track or episode, computed from its duration.  It returns zero if nothing
+// is playing.
+func (cp *CurrentlyPlaying) Remaining() time.Duration {
+	duration, ok := cp.itemDuration()
+	if !ok || duration <= cp.Progress {
+		return 0
+	}
+	return time.Duration(duration-cp.Progress) * time.Millisecond
+}
+
+// IsPlaying reports whether a track or episode is currently playing.  Unlike
+// the Playing field, it returns false when there is no current item.
+func (cp *CurrentlyPlaying) IsPlaying() bool {
+	_, ok := cp.itemDuration()
+	return ok && cp.Playing
+}
+
+// RecentlyPlayedItem is a track that the user played recently.
 type RecentlyPlayedItem struct {
 	// Track is the track information
 	Track SimpleTrack `json:"track"`
@@ -93,6 +286,30 @@ type PlaybackOffset struct {
 	URI URI `json:"uri,omitempty"`
 }
 
+// BuildPlaybackOffset returns a [PlaybackOffset] that starts playback at the
+// item with the given zero-based position in the context or URI list.
+func BuildPlaybackOffset(position int) *PlaybackOffset {
+	return &PlaybackOffset{Position: &position}
+}
+
+// BuildPlaybackOffsetURI returns a [PlaybackOffset] that starts playback at the
+// item with the given URI.
+func BuildPlaybackOffsetURI(uri URI) *PlaybackOffset {
+	return &PlaybackOffset{URI: uri}
+}
+
+// validate checks that exactly one of Position and URI is set, since Spotify
+// rejects an offset with both, and ignores one with neither.
+func (o *PlaybackOffset) validate() error {
+	if (o.Position == nil) == (o.URI == "") {
+		return errors.New("spotify: playback offset must have exactly one of a position or a URI")
+	}
+	if o.Position != nil && *o.Position < 0 {
+		return errors.New("spotify: playback offset position can't be negative")
+	}
+	return nil
+}
+
 type PlayOptions struct {
 	// DeviceID The id of the device this command is targeting. If not
 	// supplied, the user's currently active device is the target.
@@ -114,8 +331,10 @@ type PlayOptions struct {
 }
 
 // RecentlyPlayedOptions describes options for the recently-played request. All
-// fields are optional. Only one of AfterEpochMs and BeforeEpochMs may be
-// given.
+// fields are optional. Only one of After (or AfterEpochMs) and Before (or
+// BeforeEpochMs) may be given, since Spotify pages through the history with a
+// cursor in one direction: pass the PlayedAt time of the oldest item as
+// Before to get the items played before it.
 //
 // Note: it seems as if Spotify only remembers the fifty most-recent tracks.
 type RecentlyPlayedOptions struct {
@@ -130,14 +349,72 @@ type RecentlyPlayedOptions struct {
 	// BeforeEpochMs is a Unix epoch in milliseconds that describes a time
 	// before which to return songs.
 	BeforeEpochMs int64
+
+	// After is like AfterEpochMs, and takes precedence over it if it is not
+	// the zero time.
+	After time.Time
+
+	// Before is like BeforeEpochMs, and takes precedence over it if it is
+	// not the zero time.
+	Before time.Time
 }
 
+// epochMs returns t as a Unix epoch in milliseconds, or ms if t is the zero
+// time.
+func epochMs(t time.Time, ms int64) int64 {
+	if t.IsZero() {
+		return ms
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// Queue contains the item that is currently playing and the items queued up
+// after it.  Both tracks and episodes can be queued.
 type Queue struct {
-	CurrentlyPlaying FullTrack   `json:"currently_playing"`
-	Items            []FullTrack `json:"queue"`
+	// CurrentlyPlaying is the track that is currently playing.  It is the
+	// zero FullTrack if nothing or an episode is playing.
+	CurrentlyPlaying FullTrack `json:"currently_playing"`
+	// CurrentlyPlayingEpisode is the episode that is currently playing, if
+	// any.
+	CurrentlyPlayingEpisode *EpisodePage `json:"-"`
+	// Items holds the tracks in the queue, using a zero FullTrack for the
+	// items that are episodes.  Use Entries to tell tracks and episodes apart.
+	Items []FullTrack `json:"queue"`
+	// Entries holds the items in the queue, in order, each of which is either
+	// a track or an episode.
+	Entries []PlaylistItemTrack `json:"-"`
+}
+
+// UnmarshalJSON decodes the queue, telling tracks and episodes apart by their
+// type.
+func (q *Queue) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		CurrentlyPlaying PlaylistItemTrack   `json:"currently_playing"`
+		Queue            []PlaylistItemTrack `json:"queue"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*q = Queue{
+		CurrentlyPlayingEpisode: raw.CurrentlyPlaying.Episode,
+		Items:                   make([]FullTrack, len(raw.Queue)),
+		Entries:                 raw.Queue,
+	}
+	if raw.CurrentlyPlaying.Track != nil {
+		q.CurrentlyPlaying = *raw.CurrentlyPlaying.Track
+	}
+	for i, entry := range raw.Queue {
+		if entry.Track != nil {
+			q.Items[i] = *entry.Track
+		}
+	}
+	return nil
 }
 
 // PlayerDevices information about available devices for the current user.
+// A device's ID can be passed to [Client.TransferPlayback] to move playback
+// to it.
 //
 // Requires the [ScopeUserReadPlaybackState] scope in order to read information
 func (c *Client) PlayerDevices(ctx context.Context) ([]PlayerDevice, error) {
@@ -156,16 +433,34 @@ func (c *Client) PlayerDevices(ctx context.Context) ([]PlayerDevice, error) {
 // PlayerState gets information about the playing state for the current user
 // Requires the [ScopeUserReadPlaybackState] scope in order to read information
 //
-// Supported options: [Market].
+// If the user has no active device, Spotify responds without any content, and
+// PlayerState returns a nil state and a nil error.
+//
+// Supported options: [Market], [AdditionalTypes].
 func (c *Client) PlayerState(ctx context.Context, opts ...RequestOption) (*PlayerState, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "me/player"
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
 		spotifyURL += "?" + params
 	}
 
-	var result PlayerState
+	var raw json.RawMessage
 
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		// 204 No Content: there is no active device
+		return nil, nil
+	}
+
+	var result PlayerState
+	err = json.Unmarshal(raw, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -179,8 +474,13 @@ func (c *Client) PlayerState(ctx context.Context, opts ...RequestOption) (*Playe
 // Requires the [ScopeUserReadCurrentlyPlaying] scope or the [ScopeUserReadPlaybackState]
 // scope in order to read information.
 //
-// Supported options: [Market].
+// Supported options: [Market], [AdditionalTypes].
 func (c *Client) PlayerCurrentlyPlaying(ctx context.Context, opts ...RequestOption) (*CurrentlyPlaying, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "me/player/currently-playing"
 
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
@@ -212,15 +512,20 @@ func (c *Client) PlayerRecentlyPlayed(ctx context.Context) ([]RecentlyPlayedItem
 func (c *Client) PlayerRecentlyPlayedOpt(ctx context.Context, opt *RecentlyPlayedOptions) ([]RecentlyPlayedItem, error) {
 	spotifyURL := c.baseURL + "me/player/recently-played"
 	if opt != nil {
+		after, before := epochMs(opt.After, opt.AfterEpochMs), epochMs(opt.Before, opt.BeforeEpochMs)
+		if after != 0 && before != 0 {
+			return nil, errors.New("spotify: only one of after and before may be given for recently played tracks")
+		}
+
 		v := url.Values{}
 		if opt.Limit != 0 {
 			v.Set("limit", strconv.FormatInt(int64(opt.Limit), 10))
 		}
-		if opt.BeforeEpochMs != 0 {
-			v.Set("before", strconv.FormatInt(int64(opt.BeforeEpochMs), 10))
+		if before != 0 {
+			v.Set("before", strconv.FormatInt(before, 10))
 		}
-		if opt.AfterEpochMs != 0 {
-			v.Set("after", strconv.FormatInt(int64(opt.AfterEpochMs), 10))
+		if after != 0 {
+			v.Set("after", strconv.FormatInt(after, 10))
 		}
 		if params := v.Encode(); params != "" {
 			spotifyURL += "?" + params
@@ -281,6 +586,12 @@ func (c *Client) PlayOpt(ctx context.Context, opt *PlayOptions) error {
 	buf := new(bytes.Buffer)
 
 	if opt != nil {
+		if opt.PlaybackOffset != nil {
+			if err := opt.PlaybackOffset.validate(); err != nil {
+				return err
+			}
+		}
+
 		v := url.Values{}
 		if opt.DeviceID != nil {
 			v.Set("device_id", opt.DeviceID.String())
@@ -357,7 +668,11 @@ func (c *Client) GetQueue(ctx context.Context) (*Queue, error) {
 
 // QueueSong adds a song to the user's queue on the user's currently
 // active device. This call requires [ScopeUserModifyPlaybackState]
-// to modify the player state
+// to modify the player state.  The queue is played before the rest of the
+// current context, which is left as it is.
+//
+// trackID is either a track ID, or a track or episode URI such as
+// "spotify:episode:512ojhOuo1ktJprKbVcKyQ" to queue an episode.
 func (c *Client) QueueSong(ctx context.Context, trackID ID) error {
 	return c.QueueSongOpt(ctx, trackID, nil)
 }
@@ -366,11 +681,14 @@ func (c *Client) QueueSong(ctx context.Context, trackID ID) error {
 //
 // Only expects [PlayOptions.DeviceID], all other options will be ignored.
 func (c *Client) QueueSongOpt(ctx context.Context, trackID ID, opt *PlayOptions) error {
-	uri := "spotify:track:" + trackID
+	uri, err := queueURI(trackID)
+	if err != nil {
+		return err
+	}
 	spotifyURL := c.baseURL + "me/player/queue"
 	v := url.Values{}
 
-	v.Set("uri", uri.String())
+	v.Set("uri", uri)
 
 	if opt != nil {
 		if opt.DeviceID != nil {
@@ -393,6 +711,18 @@ func (c *Client) QueueSongOpt(ctx context.Context, trackID ID, opt *PlayOptions)
 	)
 }
 
+// queueURI returns the URI to queue for id, which is either a track ID or a
+// track or episode URI.
+func queueURI(id ID) (string, error) {
+	if strings.HasPrefix(string(id), "spotify:episode:") {
+		if strings.TrimPrefix(string(id), "spotify:episode:") == "" {
+			return "", errors.New("spotify: empty episode ID")
+		}
+		return string(id), nil
+	}
+	return trackURI(id)
+}
+
 // Next skips to the next track in the user's queue in the user's
 // currently active device. This call requires [ScopeUserModifyPlaybackState]
 // in order to modify the player state.
@@ -400,7 +730,7 @@ func (c *Client) Next(ctx context.Context) error {
 	return c.NextOpt(ctx, nil)
 }
 
-// NextOpt is like Next but with more options.
+// NextOpt is like [Next] but with more options.
 //
 // Only expects [PlayOptions.DeviceID], all other options will be ignored.
 func (c *Client) NextOpt(ctx context.Context, opt *PlayOptions) error {
@@ -427,7 +757,7 @@ func (c *Client) NextOpt(ctx context.Context, opt *PlayOptions) error {
 
 // Previous skips to the previous track in the user's queue on the user's
 // currently active device. This call requires [ScopeUserModifyPlaybackState]
-// in order to modify the player state
+// in order to modify the player state.
 func (c *Client) Previous(ctx context.Context) error {
 	return c.PreviousOpt(ctx, nil)
 }
@@ -459,7 +789,7 @@ func (c *Client) PreviousOpt(ctx context.Context, opt *PlayOptions) error {
 
 // Seek to the given position in the user’s currently playing track.
 //
-// The position in milliseconds to seek to. Must be a positive number.
+// The position in milliseconds to seek to. Must not be negative.
 // Passing in a position that is greater than the length of the track
 // will cause the player to start playing the next song.
 //
@@ -472,6 +802,9 @@ func (c *Client) Seek(ctx context.Context, position int) error {
 //
 // Only expects [PlayOptions.DeviceID], all other options will be ignored.
 func (c *Client) SeekOpt(ctx context.Context, position int, opt *PlayOptions) error {
+	if position < 0 {
+		return errors.New("spotify: seek position can't be negative")
+	}
 	return c.playerFuncWithOpt(
 		ctx,
 		"me/player/seek",
@@ -482,11 +815,19 @@ func (c *Client) SeekOpt(ctx context.Context, position int, opt *PlayOptions) er
 	)
 }
 
+// Repeat modes for [Client.Repeat], as also reported by [PlayerState.RepeatState].
+const (
+	RepeatTrack   = "track"
+	RepeatContext = "context"
+	RepeatOff     = "off"
+)
+
 // Repeat Set the repeat mode for the user's playback.
 //
-// Options are track, context, and off.
+// Options are [RepeatTrack], [RepeatContext], and [RepeatOff]; any other
+// state results in an error without making a request.
 //
-// Requires the ScopeUserModifyPlaybackState in order to modify the player state.
+// Requires the [ScopeUserModifyPlaybackState] in order to modify the player state.
 func (c *Client) Repeat(ctx context.Context, state string) error {
 	return c.RepeatOpt(ctx, state, nil)
 }
@@ -495,6 +836,11 @@ func (c *Client) Repeat(ctx context.Context, state string) error {
 //
 // Only expects [PlayOptions.DeviceID], all other options will be ignored.
 func (c *Client) RepeatOpt(ctx context.Context, state string, opt *PlayOptions) error {
+	switch state {
+	case RepeatTrack, RepeatContext, RepeatOff:
+	default:
+		return fmt.Errorf("spotify: invalid repeat state %q", state)
+	}
 	return c.playerFuncWithOpt(
 		ctx,
 		"me/player/repeat",
@@ -507,7 +853,8 @@ func (c *Client) RepeatOpt(ctx context.Context, state string, opt *PlayOptions)
 
 // Volume set the volume for the user's current playback device.
 //
-// Percent is must be a value from 0 to 100 inclusive.
+// Percent is must be a value from 0 to 100 inclusive; any other value results
+// in an error without making a request.
 //
 // Requires the [ScopeUserModifyPlaybackState] in order to modify the player state.
 func (c *Client) Volume(ctx context.Context, percent int) error {
@@ -518,6 +865,9 @@ func (c *Client) Volume(ctx context.Context, percent int) error {
 //
 // Only expects [PlayOptions.DeviceID], all other options will be ignored.
 func (c *Client) VolumeOpt(ctx context.Context, percent int, opt *PlayOptions) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("spotify: volume must be from 0 to 100 percent, got %d", percent)
+	}
 	return c.playerFuncWithOpt(
 		ctx,
 		"me/player/volume",
@@ -2,8 +2,13 @@ package spotify
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTransferPlaybackDeviceUnavailable(t *testing.T) {
@@ -16,13 +21,22 @@ func TestTransferPlaybackDeviceUnavailable(t *testing.T) {
 }
 
 func TestTransferPlayback(t *testing.T) {
-	client, server := testClientString(http.StatusNoContent, "")
+	var body []byte
+	client, server := testClientString(http.StatusNoContent, "", func(r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/me/player" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ = io.ReadAll(r.Body)
+	})
 	defer server.Close()
 
 	err := client.TransferPlayback(context.Background(), "newdevice", true)
 	if err != nil {
 		t.Error(err)
 	}
+	if got := strings.TrimSpace(string(body)); got != `{"device_ids":["newdevice"],"play":true}` {
+		t.Errorf("Unexpected body %s", got)
+	}
 }
 
 func TestVolume(t *testing.T) {
@@ -35,6 +49,119 @@ func TestVolume(t *testing.T) {
 	}
 }
 
+func TestPlayerControls(t *testing.T) {
+	var requests []string
+	client, server := testClientString(http.StatusNoContent, "", func(r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT, got %s", r.Method)
+		}
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+	})
+	defer server.Close()
+
+	device := ID("speaker")
+	opt := &PlayOptions{DeviceID: &device}
+	ctx := context.Background()
+	for _, err := range []error{
+		client.VolumeOpt(ctx, 100, opt),
+		client.SeekOpt(ctx, 25000, opt),
+		client.ShuffleOpt(ctx, true, opt),
+		client.RepeatOpt(ctx, RepeatContext, opt),
+	} {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	want := []string{
+		"/me/player/volume?device_id=speaker&volume_percent=100",
+		"/me/player/seek?device_id=speaker&position_ms=25000",
+		"/me/player/shuffle?device_id=speaker&state=true",
+		"/me/player/repeat?device_id=speaker&state=context",
+	}
+	if strings.Join(requests, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+
+	requests = nil
+	for _, err := range []error{
+		client.Volume(ctx, -1),
+		client.Volume(ctx, 101),
+		client.Seek(ctx, -1),
+		client.Repeat(ctx, "all"),
+	} {
+		if err == nil {
+			t.Error("Expected an error for invalid input")
+		}
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no requests for invalid input, got %v", requests)
+	}
+}
+
+func TestPlayerControlsNoActiveDevice(t *testing.T) {
+	client, server := testClientString(http.StatusNotFound, `{ "error": { "status": 404, "message": "Player command failed: No active device found", "reason": "NO_ACTIVE_DEVICE" } }`)
+	defer server.Close()
+
+	err := client.Shuffle(context.Background(), false)
+	if e, ok := err.(Error); !ok || e.Status != http.StatusNotFound || e.Reason != ReasonNoActiveDevice {
+		t.Errorf("Expected a 404 Error for no active device, got %v", err)
+	}
+}
+
+func TestPlayerPremiumRequired(t *testing.T) {
+	client, server := testClientString(http.StatusForbidden, `{ "error": { "status": 403, "message": "Player command failed: Premium required", "reason": "PREMIUM_REQUIRED" } }`)
+	defer server.Close()
+
+	err := client.Play(context.Background())
+	var e Error
+	if !errors.As(err, &e) || e.Status != http.StatusForbidden || e.Reason != ReasonPremiumRequired {
+		t.Errorf("Expected a 403 Error for a non-Premium account, got %v", err)
+	}
+	if e.Message != "Player command failed: Premium required" {
+		t.Errorf("Unexpected message '%s'", e.Message)
+	}
+}
+
+func TestNextAndPrevious(t *testing.T) {
+	var requests []string
+	client, server := testClientString(http.StatusNoContent, "", func(r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+	})
+	defer server.Close()
+
+	device := ID("speaker")
+	ctx := context.Background()
+	for _, err := range []error{
+		client.Next(ctx),
+		client.NextOpt(ctx, &PlayOptions{DeviceID: &device}),
+		client.Previous(ctx),
+		client.PreviousOpt(ctx, &PlayOptions{DeviceID: &device}),
+	} {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	want := []string{
+		"POST /me/player/next?",
+		"POST /me/player/next?device_id=speaker",
+		"POST /me/player/previous?",
+		"POST /me/player/previous?device_id=speaker",
+	}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestNextNoActiveDevice(t *testing.T) {
+	client, server := testClientString(http.StatusNotFound, `{ "error": { "status": 404, "message": "Player command failed: No active device found" } }`)
+	defer server.Close()
+
+	err := client.Next(context.Background())
+	if e, ok := err.(Error); !ok || e.Status != http.StatusNotFound {
+		t.Errorf("Expected a 404 Error, got %v", err)
+	}
+}
+
 func TestQueue(t *testing.T) {
 	client, server := testClientString(http.StatusNoContent, "")
 	defer server.Close()
@@ -45,6 +172,33 @@ func TestQueue(t *testing.T) {
 	}
 }
 
+func TestQueueEpisode(t *testing.T) {
+	var uris []string
+	client, server := testClientString(http.StatusNoContent, "", func(r *http.Request) {
+		uris = append(uris, r.URL.Query().Get("uri"))
+		if device := r.URL.Query().Get("device_id"); device != "speaker" {
+			t.Errorf("Expected device 'speaker', got '%s'", device)
+		}
+	})
+	defer server.Close()
+
+	device := ID("speaker")
+	opt := &PlayOptions{DeviceID: &device}
+	for _, id := range []ID{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "spotify:track:4JpKVNYnVcJ8tuMKjAj50A", "4JpKVNYnVcJ8tuMKjAj50A"} {
+		if err := client.QueueSongOpt(context.Background(), id, opt); err != nil {
+			t.Error(err)
+		}
+	}
+	want := []string{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "spotify:track:4JpKVNYnVcJ8tuMKjAj50A", "spotify:track:4JpKVNYnVcJ8tuMKjAj50A"}
+	if strings.Join(uris, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected URIs %v, got %v", want, uris)
+	}
+
+	if err := client.QueueSong(context.Background(), "spotify:album:0sNOF9WDwhWunNAHPD3Baj"); err == nil {
+		t.Error("Expected an error for an album URI")
+	}
+}
+
 func TestPlayerDevices(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/player_available_devices.txt")
 	defer server.Close()
@@ -64,6 +218,25 @@ func TestPlayerDevices(t *testing.T) {
 	if list[1].Volume != 0 {
 		t.Error("Expected null becomes 0")
 	}
+	if list[1].ID != "75169ece5815c496c340421ad09cf94e8ddc1497" || !list[1].Active || list[1].Type != "Smartphone" {
+		t.Errorf("Unexpected device %#v", list[1])
+	}
+}
+
+func TestPlayerDevicesPrivateSession(t *testing.T) {
+	client, server := testClientString(http.StatusOK, `{ "devices": [
+		{ "id": "laptop", "is_private_session": false },
+		{ "id": "phone", "is_private_session": true }
+	] }`)
+	defer server.Close()
+
+	list, err := client.PlayerDevices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].PrivateSession || !list[1].PrivateSession {
+		t.Errorf("Expected only the second device to be in a private session, got %+v", list)
+	}
 }
 
 func TestPlayerState(t *testing.T) {
@@ -95,6 +268,70 @@ func TestPlayerState(t *testing.T) {
 	if state.Playing {
 		t.Error("Expected not to be playing")
 	}
+
+	if state.CurrentlyPlayingType != "track" || state.Episode != nil {
+		t.Errorf("Expected a track, got type '%s'", state.CurrentlyPlayingType)
+	}
+
+	if state.Device.Name != "Pixel" || !state.ShuffleState || state.RepeatState != "off" {
+		t.Errorf("Unexpected device, shuffle or repeat state: %s, %t, %s", state.Device.Name, state.ShuffleState, state.RepeatState)
+	}
+}
+
+func TestPlayerStateActions(t *testing.T) {
+	client, server := testClientString(http.StatusOK, `{
+		"is_playing": true,
+		"actions": { "disallows": { "resuming": true, "skipping_prev": true } }
+	}`)
+	defer server.Close()
+
+	state, err := client.PlayerState(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PlayerDisallows{Resuming: true, SkippingPrev: true}
+	if state.Actions.Disallows != want {
+		t.Errorf("Expected %+v to be disallowed, got %+v", want, state.Actions.Disallows)
+	}
+}
+
+func TestPlayerStateEpisode(t *testing.T) {
+	client, server := testClientFile(http.StatusOK, "test_data/player_state_episode.txt")
+	defer server.Close()
+
+	state, err := client.PlayerState(context.Background(), AdditionalTypes(EpisodeAdditionalType))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.CurrentlyPlayingType != "episode" || state.Item != nil {
+		t.Fatalf("Expected an episode and no track, got type '%s'", state.CurrentlyPlayingType)
+	}
+	if state.Episode == nil || state.Episode.Show.Name != "Uncommon Core" {
+		t.Fatal("Expected an episode of Uncommon Core")
+	}
+	if d := state.Remaining(); d != (5485408-1200000)*time.Millisecond {
+		t.Error("Unexpected remaining duration:", d)
+	}
+	if !state.IsPlaying() {
+		t.Error("Expected to be playing")
+	}
+	if state.Device.Volume != 60 || state.ShuffleState || state.RepeatState != "context" {
+		t.Errorf("Unexpected volume, shuffle or repeat state: %d, %t, %s", state.Device.Volume, state.ShuffleState, state.RepeatState)
+	}
+}
+
+func TestPlayerStateNoActiveDevice(t *testing.T) {
+	client, server := testClientString(http.StatusNoContent, "")
+	defer server.Close()
+
+	state, err := client.PlayerState(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != nil {
+		t.Error("Expected a nil state without an active device")
+	}
 }
 
 func TestPlayerCurrentlyPlaying(t *testing.T) {
@@ -123,11 +360,57 @@ func TestPlayerCurrentlyPlaying(t *testing.T) {
 		t.Error("Expected progress to be 102509")
 	}
 
-	if state.Playing {
+	if d := state.ProgressDuration(); d != 102509*time.Millisecond {
+		t.Error("Unexpected progress duration:", d)
+	}
+
+	if d := state.Remaining(); d != (196226-102509)*time.Millisecond {
+		t.Error("Unexpected remaining duration:", d)
+	}
+
+	if state.Playing || state.IsPlaying() {
 		t.Error("Expected not to be playing")
 	}
 }
 
+func TestPlayerCurrentlyPlayingRelinked(t *testing.T) {
+	var market string
+	client, server := testClientFile(http.StatusOK, "test_data/player_currently_playing_relinked.txt", func(r *http.Request) {
+		market = r.URL.Query().Get("market")
+	})
+	defer server.Close()
+
+	state, err := client.PlayerCurrentlyPlaying(context.Background(), Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if market != CountryUnitedKingdom {
+		t.Errorf("Expected market %s, got '%s'", CountryUnitedKingdom, market)
+	}
+	if state.Item == nil {
+		t.Fatal("Expected item to be a track")
+	}
+	if state.Item.ID != "6kLCHFM39wkFjOuyPGLGeQ" {
+		t.Error("Expected the relinked track to be playing, got", state.Item.ID)
+	}
+	if state.Item.LinkedFrom == nil || state.Item.LinkedFrom.ID != "1lkvpmrCaXK8QtliFDcHBO" {
+		t.Error("Expected linked_from to point to the original track")
+	}
+	if !state.IsPlaying() {
+		t.Error("Expected to be playing")
+	}
+}
+
+func TestCurrentlyPlayingNothing(t *testing.T) {
+	state := CurrentlyPlaying{Progress: 1000, Playing: true}
+	if state.ProgressDuration() != 0 || state.Remaining() != 0 {
+		t.Error("Expected zero durations when nothing is playing")
+	}
+	if state.IsPlaying() {
+		t.Error("Expected not to be playing without an item")
+	}
+}
+
 func TestPlayerRecentlyPlayed(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/player_recently_played.txt")
 	defer server.Close()
@@ -156,6 +439,40 @@ func TestPlayerRecentlyPlayed(t *testing.T) {
 	}
 }
 
+func TestPlayerRecentlyPlayedOpt(t *testing.T) {
+	var query url.Values
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		http.ServeFile(w, r, "test_data/player_recently_played.txt")
+	}))
+	defer server.Close()
+
+	before := time.Date(2017, 5, 27, 20, 7, 54, 721000000, time.UTC)
+	items, err := client.PlayerRecentlyPlayedOpt(context.Background(), &RecentlyPlayedOptions{Limit: 20, Before: before})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("before") != "1495915674721" || query.Get("limit") != "20" || query.Get("after") != "" {
+		t.Errorf("Unexpected query %v", query)
+	}
+	if ctx := items[2].PlaybackContext; ctx.Type != "playlist" || ctx.URI != "spotify:user:dsoprea:playlist:23uR7PylGNatdoUwf04sax" {
+		t.Errorf("Unexpected playback context %#v", ctx)
+	}
+
+	_, err = client.PlayerRecentlyPlayedOpt(context.Background(), &RecentlyPlayedOptions{AfterEpochMs: 1495915674721})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("after") != "1495915674721" {
+		t.Errorf("Unexpected query %v", query)
+	}
+
+	_, err = client.PlayerRecentlyPlayedOpt(context.Background(), &RecentlyPlayedOptions{After: before, BeforeEpochMs: 1495915674721})
+	if err == nil {
+		t.Error("Expected an error for both after and before")
+	}
+}
+
 func TestPlayArgsError(t *testing.T) {
 	json := `{
 		"error" : {
@@ -172,6 +489,47 @@ func TestPlayArgsError(t *testing.T) {
 	}
 }
 
+func TestPlayOffset(t *testing.T) {
+	var body string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = strings.TrimSpace(string(b))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	playlist := URI("spotify:playlist:37i9dQZF1DXcBWIGoYBM5M")
+	err := client.PlayOpt(context.Background(), &PlayOptions{
+		PlaybackContext: &playlist,
+		PlaybackOffset:  BuildPlaybackOffset(0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"context_uri":"spotify:playlist:37i9dQZF1DXcBWIGoYBM5M","offset":{"position":0}}`; body != expected {
+		t.Errorf("Expected body %s, got %s", expected, body)
+	}
+
+	err = client.PlayOpt(context.Background(), &PlayOptions{
+		PlaybackContext: &playlist,
+		PlaybackOffset:  BuildPlaybackOffsetURI("spotify:track:4iV5W9uYEdYUVa79Axb7Rh"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"context_uri":"spotify:playlist:37i9dQZF1DXcBWIGoYBM5M","offset":{"uri":"spotify:track:4iV5W9uYEdYUVa79Axb7Rh"}}`; body != expected {
+		t.Errorf("Expected body %s, got %s", expected, body)
+	}
+
+	both := BuildPlaybackOffset(1)
+	both.URI = "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"
+	for _, offset := range []*PlaybackOffset{both, {}, BuildPlaybackOffset(-1)} {
+		if err := client.PlayOpt(context.Background(), &PlayOptions{PlaybackOffset: offset}); err == nil {
+			t.Errorf("Expected an error for offset %+v", offset)
+		}
+	}
+}
+
 func TestGetQueue(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/get_queue.txt")
 	defer server.Close()
@@ -197,3 +555,34 @@ func TestGetQueue(t *testing.T) {
 		t.Error("Expected 'Know Your Enemy', got", p.Name)
 	}
 }
+
+func TestGetQueueWithEpisodes(t *testing.T) {
+	client, server := testClientString(http.StatusOK, `{
+		"currently_playing": { "type": "episode", "id": "512ojhOuo1ktJprKbVcKyQ", "name": "Episode 1" },
+		"queue": [
+			{ "type": "track", "id": "4JpKVNYnVcJ8tuMKjAj50A", "name": "Track 1" },
+			{ "type": "episode", "id": "2NRANZE9UCmPAS5XVbXL40", "name": "Episode 2" }
+		]
+	}`)
+	defer server.Close()
+
+	queue, err := client.GetQueue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queue.CurrentlyPlayingEpisode == nil || queue.CurrentlyPlayingEpisode.Name != "Episode 1" {
+		t.Errorf("Expected the current episode, got %#v", queue.CurrentlyPlayingEpisode)
+	}
+	if queue.CurrentlyPlaying.ID != "" {
+		t.Errorf("Expected no current track, got %s", queue.CurrentlyPlaying.ID)
+	}
+	if len(queue.Entries) != 2 || len(queue.Items) != 2 {
+		t.Fatalf("Expected 2 queued items, got %d entries and %d items", len(queue.Entries), len(queue.Items))
+	}
+	if queue.Entries[0].Track == nil || queue.Items[0].Name != "Track 1" {
+		t.Errorf("Expected the first item to be a track, got %#v", queue.Entries[0])
+	}
+	if queue.Entries[1].Episode == nil || queue.Entries[1].Episode.Name != "Episode 2" || queue.Items[1].ID != "" {
+		t.Errorf("Expected the second item to be an episode, got %#v", queue.Entries[1])
+	}
+}
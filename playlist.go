@@ -5,11 +5,16 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // PlaylistTracks contains details about the tracks in a playlist.
@@ -27,7 +32,9 @@ type SimplePlaylist struct {
 	// Note: only non-collaborative playlists are currently returned by Spotify's Web API.
 	Collaborative bool `json:"collaborative"`
 	// The playlist description. Empty string if no description is set.
-	Description  string            `json:"description"`
+	Description string `json:"description"`
+	// Known external URLs for this playlist, keyed by type.  The "spotify"
+	// key holds the open.spotify.com link; see [SimplePlaylist.WebURL].
 	ExternalURLs map[string]string `json:"external_urls"`
 	// A link to the Web API endpoint providing full details of the playlist.
 	Endpoint string `json:"href"`
@@ -39,6 +46,10 @@ type SimplePlaylist struct {
 	Name     string  `json:"name"`
 	Owner    User    `json:"owner"`
 	IsPublic bool    `json:"public"`
+	// The color used to theme the playlist in Spotify's clients, as a hex
+	// string such as "#FFC864".  Spotify usually reports it as null, which
+	// leaves this field empty.
+	PrimaryColor string `json:"primary_color"`
 	// The version identifier for the current playlist. Can be supplied in other
 	// requests to target a specific playlist version.
 	SnapshotID string `json:"snapshot_id"`
@@ -48,12 +59,60 @@ type SimplePlaylist struct {
 	URI    URI            `json:"uri"`
 }
 
+// WebURL returns the open.spotify.com link for the playlist, suitable for
+// sharing.  It falls back to a link built from the playlist ID when Spotify
+// didn't return one (for example, because a [Fields] filter excluded it), and
+// returns the empty string if neither is available.
+func (p SimplePlaylist) WebURL() string {
+	if u := p.ExternalURLs["spotify"]; u != "" {
+		return u
+	}
+	if p.ID == "" {
+		return ""
+	}
+	return "https://open.spotify.com/playlist/" + string(p.ID)
+}
+
+// IsEditorial reports whether the playlist is an editorial playlist, curated
+// and owned by Spotify itself rather than by a user.  Editorial playlists
+// can't be modified by users.
+func (p SimplePlaylist) IsEditorial() bool {
+	return p.Owner.ID == "spotify"
+}
+
 // FullPlaylist provides extra playlist data in addition to the data provided by [SimplePlaylist].
 type FullPlaylist struct {
 	SimplePlaylist
 	// Information about the followers of this playlist.
-	Followers Followers         `json:"followers"`
-	Tracks    PlaylistTrackPage `json:"tracks"`
+	Followers Followers `json:"followers"`
+	// Tracks holds the first page of the playlist's tracks.  Items that
+	// aren't tracks, such as podcast episodes, are left as a zero
+	// [FullTrack]; use Items to get at them.
+	Tracks PlaylistTrackPage `json:"tracks"`
+	// Items holds the same page as Tracks, with tracks and episodes told
+	// apart as they are by [Client.GetPlaylistItems].  It is only filled in
+	// when decoding: a FullPlaylist is marshalled with just Tracks, so
+	// marshalling a decoded playlist drops its episodes.
+	Items PlaylistItemPage `json:"-"`
+}
+
+// UnmarshalJSON decodes the first page of the playlist's items into both
+// Tracks and Items.
+func (p *FullPlaylist) UnmarshalJSON(b []byte) error {
+	// fullPlaylist has the fields of FullPlaylist, but not this method, so
+	// it can be decoded without recursing.
+	type fullPlaylist FullPlaylist
+	var v struct {
+		fullPlaylist
+		Tracks PlaylistItemPage `json:"tracks"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*p = FullPlaylist(v.fullPlaylist)
+	p.Items = v.Tracks
+	p.Tracks = *v.Tracks.trackPage()
+	return nil
 }
 
 // FeaturedPlaylists gets a [list of playlists featured by Spotify].
@@ -121,7 +180,8 @@ func buildFollowURI(url string, playlist ID) string {
 }
 
 // GetPlaylistsForUser [gets a list of the playlists] owned or followed by a
-// particular Spotify user.
+// particular Spotify user.  Spotify mixes both kinds of playlists in the
+// result; use [GetUserOwnedPlaylists] to only get the ones the user created.
 //
 // Private playlists and collaborative playlists are only retrievable for the
 // current user.  In order to read private playlists, the user must have granted
@@ -149,20 +209,54 @@ func (c *Client) GetPlaylistsForUser(ctx context.Context, userID string, opts ..
 	return &result, err
 }
 
-// GetPlaylist [fetches a playlist] from spotify.
+// GetUserOwnedPlaylists gets all of the playlists owned by a particular
+// Spotify user, excluding playlists the user merely follows.  It pages through
+// the results of [GetPlaylistsForUser] and keeps the playlists whose owner ID
+// matches userID.  The same scope restrictions apply.
+func (c *Client) GetUserOwnedPlaylists(ctx context.Context, userID string) ([]SimplePlaylist, error) {
+	page, err := c.GetPlaylistsForUser(ctx, userID, Limit(50))
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []SimplePlaylist
+	for {
+		for _, p := range page.Playlists {
+			if p.Owner.ID == userID {
+				owned = append(owned, p)
+			}
+		}
+
+		err = c.NextPage(ctx, page)
+		if err == ErrNoMorePages {
+			return owned, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// GetPlaylist [fetches a playlist] from spotify.  As with [GetPlaylistItems],
+// both tracks and episodes are requested unless overridden with
+// [AdditionalTypes] or [WithDefaultAdditionalTypes].
 //
-// Supported options: [Fields].
+// Supported options: [Fields], [EnsureSnapshot], [AdditionalTypes].
 //
 // [fetches a playlist]: https://developer.spotify.com/documentation/web-api/reference/get-playlist
 func (c *Client) GetPlaylist(ctx context.Context, playlistID ID, opts ...RequestOption) (*FullPlaylist, error) {
 	spotifyURL := fmt.Sprintf("%splaylists/%s", c.baseURL, playlistID)
-	if params := processOptions(opts...).urlParams.Encode(); params != "" {
+	params, err := c.additionalTypesParams(opts)
+	if err != nil {
+		return nil, err
+	}
+	if params := params.Encode(); params != "" {
 		spotifyURL += "?" + params
 	}
 
 	var playlist FullPlaylist
 
-	err := c.get(ctx, spotifyURL, &playlist)
+	err = c.get(ctx, spotifyURL, &playlist)
 	if err != nil {
 		return nil, err
 	}
@@ -170,10 +264,49 @@ func (c *Client) GetPlaylist(ctx context.Context, playlistID ID, opts ...Request
 	return &playlist, err
 }
 
+// PlaylistChangedSince reports whether a playlist has changed since the
+// version identified by lastSnapshot, and returns the playlist's current
+// snapshot ID.  Only the snapshot ID is requested from Spotify, using the
+// [Fields] option, which makes this much cheaper than fetching the whole
+// playlist when polling for changes.  An empty lastSnapshot is always
+// considered changed.
+func (c *Client) PlaylistChangedSince(ctx context.Context, playlistID ID, lastSnapshot string) (changed bool, newSnapshot string, err error) {
+	playlist, err := c.GetPlaylist(ctx, playlistID, Fields("snapshot_id"))
+	if err != nil {
+		return false, "", err
+	}
+
+	return playlist.SnapshotID != lastSnapshot, playlist.SnapshotID, nil
+}
+
+// playlistMetadataFields selects every field of a playlist except its items,
+// of which only the total and the link to them are kept.
+var playlistMetadataFields = NewFields(
+	"collaborative", "description", "external_urls", "followers", "href", "id", "images",
+	"name", "owner", "primary_color", "public", "snapshot_id", "type", "uri",
+).Nested("tracks", NewFields("href", "total")).String()
+
+// GetPlaylistMetadata fetches a playlist like [GetPlaylist], but uses the
+// [Fields] option to leave out its first page of items, which makes the
+// response much smaller when only the playlist's details are needed.  The
+// returned playlist's Tracks and Items pages are empty, apart from their
+// Total and Endpoint.
+func (c *Client) GetPlaylistMetadata(ctx context.Context, playlistID ID) (*FullPlaylist, error) {
+	return c.GetPlaylist(ctx, playlistID, Fields(playlistMetadataFields))
+}
+
 // GetPlaylistTracks [gets full details of the tracks in a playlist], given the
 // playlist's Spotify ID.
 //
-// Supported options: [Limit], [Offset], [Market], [Fields].
+// GetPlaylistTracks and [GetPlaylistItems] hit the same endpoint.  The "items"
+// variant is the canonical one: it returns a [PlaylistItemPage] whose entries
+// can hold either a track or an episode.  GetPlaylistTracks is kept for
+// compatibility; it delegates to [GetPlaylistItems], only asking for tracks
+// unless overridden with [AdditionalTypes], and flattens the result into a
+// [PlaylistTrackPage].  Entries that aren't tracks (such as unavailable content,
+// or episodes if they were requested) are returned as a zero [FullTrack].
+//
+// Supported options: [Limit], [Offset], [Market], [Fields], [AdditionalTypes].
 //
 // Deprecated: the Spotify api is moving towards supporting both tracks and episodes. Use [GetPlaylistItems] which
 // supports these.
@@ -182,19 +315,125 @@ func (c *Client) GetPlaylistTracks(
 	playlistID ID,
 	opts ...RequestOption,
 ) (*PlaylistTrackPage, error) {
-	spotifyURL := fmt.Sprintf("%splaylists/%s/tracks", c.baseURL, playlistID)
-	if params := processOptions(opts...).urlParams.Encode(); params != "" {
-		spotifyURL += "?" + params
+	opts = append([]RequestOption{AdditionalTypes(TrackAdditionalType)}, opts...)
+
+	items, err := c.GetPlaylistItems(ctx, playlistID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return items.trackPage(), nil
+}
+
+// GetPlaylistTracksPage gets the single page of a playlist's tracks that
+// starts at offset and holds at most limit tracks, straight from the
+// playlist's tracks endpoint.  It is meant for windowed loading, such as a
+// virtualized list that fetches pages as they scroll into view.  The page's
+// Next and Previous URLs can be followed with [Client.NextPage] and
+// [Client.PreviousPage].
+//
+// The limit must be between 1 and 100.  offset and limit take precedence over
+// the [Offset] and [Limit] options.
+//
+// Supported options: [Market], [Fields], [AdditionalTypes].
+func (c *Client) GetPlaylistTracksPage(ctx context.Context, playlistID ID, offset, limit int, opts ...RequestOption) (*PlaylistTrackPage, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("spotify: offset must not be negative, got %d", offset)
+	}
+	if limit < 1 || limit > 100 {
+		return nil, fmt.Errorf("spotify: limit must be between 1 and 100, got %d", limit)
 	}
 
-	var result PlaylistTrackPage
+	opts = append(opts[:len(opts):len(opts)], Offset(offset), Limit(limit))
+	return c.GetPlaylistTracks(ctx, playlistID, opts...)
+}
 
-	err := c.get(ctx, spotifyURL, &result)
+// PlaylistTrackIterator steps through the tracks of a playlist one at a time,
+// fetching the following pages as needed.  Use [GetPlaylistTracksIterator] to
+// create one.
+type PlaylistTrackIterator struct {
+	c      *Client
+	page   *PlaylistItemPage
+	tracks []PlaylistTrack
+	index  int
+	err    error
+	// max is the number of tracks to return, or 0 for all of them, and
+	// returned the number returned so far.
+	max      int
+	returned int
+}
+
+// GetPlaylistTracksIterator returns an iterator over the tracks of a playlist,
+// starting with the page that [GetPlaylistTracks] would return for the same
+// options.  The first page is fetched right away, so that an invalid playlist
+// ID or missing scope is reported here rather than by the first call to
+// [PlaylistTrackIterator.Next].
+//
+// Supported options: [Limit], [Offset], [Market], [Fields], [AdditionalTypes], [Max].
+func (c *Client) GetPlaylistTracksIterator(ctx context.Context, playlistID ID, opts ...RequestOption) (*PlaylistTrackIterator, error) {
+	opts = append([]RequestOption{AdditionalTypes(TrackAdditionalType)}, opts...)
+
+	page, err := c.GetPlaylistItems(ctx, playlistID, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	return &PlaylistTrackIterator{
+		c:      c,
+		page:   page,
+		tracks: page.trackPage().Tracks,
+		max:    processOptions(opts...).maxItems,
+	}, nil
+}
+
+// Next returns the next track of the playlist.  The boolean result is false
+// once every track has been returned.  When the tracks of the current page
+// are exhausted, Next fetches the next page with ctx; if that fails, the error
+// is returned, and also by every later call.
+//
+// No request is made for a page that can't hold any tracks, such as after a
+// full last page, or for an empty playlist, nor once [Max] tracks have been
+// returned.
+func (it *PlaylistTrackIterator) Next(ctx context.Context) (PlaylistTrack, bool, error) {
+	for it.err == nil {
+		if it.max > 0 && it.returned >= it.max {
+			return PlaylistTrack{}, false, nil
+		}
+		if it.index < len(it.tracks) {
+			it.index++
+			it.returned++
+			return it.tracks[it.index-1], true, nil
+		}
+		if it.done() {
+			return PlaylistTrack{}, false, nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			break
+		}
+		if err := it.c.NextPage(ctx, it.page); err != nil {
+			if err == ErrNoMorePages {
+				return PlaylistTrack{}, false, nil
+			}
+			it.err = err
+			break
+		}
+		it.tracks, it.index = it.page.trackPage().Tracks, 0
+	}
+	return PlaylistTrack{}, false, it.err
+}
+
+// done reports whether the current page is the last one.  Besides a missing
+// next URL, an empty page or one that reaches the total number of items ends
+// the iteration.  The total is only trusted when it is set, since a [Fields]
+// filter can leave it out.
+func (it *PlaylistTrackIterator) done() bool {
+	p := it.page
+	if p.Next == "" || len(p.Items) == 0 {
+		return true
+	}
+	return p.Total > 0 && int(p.Offset)+len(p.Items) >= int(p.Total)
 }
 
 // PlaylistItem contains info about an item in a playlist.
@@ -205,7 +444,8 @@ type PlaylistItem struct {
 	// Warning: very old playlists may not populate this value.
 	AddedAt string `json:"added_at"`
 	// The Spotify user who added the track to the playlist.
-	// Warning: very old playlists may not populate this value.
+	// Warning: very old playlists may not populate this value.  When
+	// Spotify reports it as null, AddedBy is left as the zero User.
 	AddedBy User `json:"added_by"`
 	// Whether this track is a local file or not.
 	IsLocal bool `json:"is_local"`
@@ -213,9 +453,94 @@ type PlaylistItem struct {
 	Track PlaylistItemTrack `json:"track"`
 }
 
+// AddedByID returns the ID of the user who added the item to the playlist, or
+// an empty string if Spotify didn't report who added it.
+func (i *PlaylistItem) AddedByID() string {
+	return i.AddedBy.ID
+}
+
+// ItemsByContributor groups playlist items by the ID of the user who added
+// them, such as the items returned by [Client.GetAllPlaylistItems] for a
+// collaborative playlist.  Within each group, the items keep their order.
+// Items for which Spotify didn't report who added them are grouped under the
+// empty ID.
+func ItemsByContributor(items []PlaylistItem) map[ID][]PlaylistItem {
+	result := make(map[ID][]PlaylistItem)
+	for _, item := range items {
+		id := ID(item.AddedByID())
+		result[id] = append(result[id], item)
+	}
+	return result
+}
+
+// EpisodesByShow groups the episodes among playlist items by the ID of the
+// show they belong to, using the summary of the show that Spotify includes
+// with each episode, so that no further requests are needed.  Within each
+// group, the items keep their order.  Items that aren't episodes are left
+// out.
+func EpisodesByShow(items []PlaylistItem) map[ID][]PlaylistItem {
+	result := make(map[ID][]PlaylistItem)
+	for _, item := range items {
+		if episode := item.Track.Episode; episode != nil {
+			id := episode.Show.ID
+			result[id] = append(result[id], item)
+		}
+	}
+	return result
+}
+
+// GetPlaylistWithContributors gets a playlist along with the public profiles
+// of everyone who added items to it, keyed by user ID, which is everything
+// needed to show who added each item of a collaborative playlist.  Unlike
+// [GetPlaylist], the Tracks and Items pages of the returned playlist hold
+// every item of the playlist rather than just the first page.
+//
+// The profiles are fetched with [GetUsersPublicProfiles].  If some of them
+// can't be fetched, the playlist and the profiles that were fetched are
+// returned along with a [BatchError] for the others.
+func (c *Client) GetPlaylistWithContributors(ctx context.Context, playlistID ID) (*FullPlaylist, map[ID]*User, error) {
+	playlist, err := c.GetPlaylist(ctx, playlistID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := playlist.Items
+	for {
+		err := c.NextPage(ctx, &page)
+		if err == ErrNoMorePages {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		playlist.Items.Items = append(playlist.Items.Items, page.Items...)
+	}
+	playlist.Items.Next = ""
+	playlist.Tracks = *playlist.Items.trackPage()
+
+	var userIDs []ID
+	seen := make(map[ID]bool)
+	for _, track := range playlist.Tracks.Tracks {
+		if id := ID(track.AddedByID()); id != "" && !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	users, err := c.GetUsersPublicProfiles(ctx, userIDs...)
+	return playlist, users, err
+}
+
 // PlaylistItemTrack is a union type for both tracks and episodes. If both
 // values are null, it's likely that the piece of content is not available in
 // the configured market.
+//
+// Tracks and episodes are told apart by their "type" field.  If a [Fields]
+// filter leaves it out, the item's "album" or "show" field (only tracks have
+// an album, and only episodes have a show) or its "uri" is used instead, so a
+// filter should keep at least one of type, album, show or uri, for example
+// "items(track(name,album(name)))".  Items with none of them are assumed to
+// be tracks.
 type PlaylistItemTrack struct {
 	Track   *FullTrack
 	Episode *EpisodePage
@@ -230,22 +555,39 @@ func (t *PlaylistItemTrack) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
-	itemType := struct {
-		Type string `json:"type"`
+	item := struct {
+		Type  string          `json:"type"`
+		Album json.RawMessage `json:"album"`
+		Show  json.RawMessage `json:"show"`
+		URI   URI             `json:"uri"`
 	}{}
 
-	err := json.Unmarshal(b, &itemType)
+	err := json.Unmarshal(b, &item)
 	if err != nil {
 		return err
 	}
 
-	switch itemType.Type {
+	itemType := item.Type
+	if itemType == "" {
+		// the type was excluded, for example by a Fields filter, so fall
+		// back to the fields that only one of the types has
+		switch {
+		case item.Album != nil:
+			itemType = "track"
+		case item.Show != nil || strings.HasPrefix(string(item.URI), "spotify:episode:"):
+			itemType = "episode"
+		default:
+			itemType = "track"
+		}
+	}
+
+	switch itemType {
 	case "episode":
 		return json.Unmarshal(b, &t.Episode)
 	case "track":
 		return json.Unmarshal(b, &t.Track)
 	default:
-		return fmt.Errorf("unrecognized item type: %s", itemType.Type)
+		return fmt.Errorf("unrecognized item type: %s", itemType)
 	}
 }
 
@@ -255,31 +597,243 @@ type PlaylistItemPage struct {
 	Items []PlaylistItem `json:"items"`
 }
 
+// trackPage flattens p into a [PlaylistTrackPage], using a zero [FullTrack]
+// for items that aren't tracks.
+func (p *PlaylistItemPage) trackPage() *PlaylistTrackPage {
+	result := PlaylistTrackPage{
+		basePage: p.basePage,
+		Tracks:   make([]PlaylistTrack, len(p.Items)),
+	}
+	for i, item := range p.Items {
+		result.Tracks[i] = PlaylistTrack{
+			AddedAt: item.AddedAt,
+			AddedBy: item.AddedBy,
+			IsLocal: item.IsLocal,
+		}
+		if item.Track.Track != nil {
+			result.Tracks[i].Track = *item.Track.Track
+		}
+	}
+	return &result
+}
+
 // GetPlaylistItems [gets full details of the items in a playlist], given the
-// playlist's [Spotify ID].
+// playlist's [Spotify ID].  Unless overridden with [AdditionalTypes] or
+// [WithDefaultAdditionalTypes], both tracks and episodes are requested.  An
+// error is returned, without making a request, for types other than
+// [TrackAdditionalType] and [EpisodeAdditionalType].  This
+// is the canonical way to read the contents of a playlist; [GetPlaylistTracks]
+// is built on top of it.
 //
-// Supported options: [Limit], [Offset], [Market], [Fields].
+// Supported options: [Limit], [Offset], [Market], [Fields], [AdditionalTypes].
 //
 // [gets full details of the items in a playlist]: https://developer.spotify.com/documentation/web-api/reference/get-playlists-tracks
 // [Spotify ID]: https://developer.spotify.com/documentation/web-api/#spotify-uris-and-ids
 func (c *Client) GetPlaylistItems(ctx context.Context, playlistID ID, opts ...RequestOption) (*PlaylistItemPage, error) {
+	spotifyURL, err := c.playlistItemsURL(ctx, playlistID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PlaylistItemPage
+
+	err = c.get(ctx, spotifyURL, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// playlistItemsURL returns the URL of the first page of the items of a
+// playlist, with the client's default market and additional types applied.
+func (c *Client) playlistItemsURL(ctx context.Context, playlistID ID, opts []RequestOption) (string, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
 	spotifyURL := fmt.Sprintf("%splaylists/%s/tracks", c.baseURL, playlistID)
+	params, err := c.additionalTypesParams(opts)
+	if err != nil {
+		return "", err
+	}
+	if params := params.Encode(); params != "" {
+		spotifyURL += "?" + params
+	}
+	return spotifyURL, nil
+}
 
+// additionalTypesParams processes opts with the client's default additional
+// types applied, and validates the resulting types.
+func (c *Client) additionalTypesParams(opts []RequestOption) (url.Values, error) {
 	// Add default as the first option so it gets override by url.Values#Set
-	opts = append([]RequestOption{AdditionalTypes(EpisodeAdditionalType, TrackAdditionalType)}, opts...)
+	defaultTypes := c.defaultAdditionalTypes
+	if len(defaultTypes) == 0 {
+		defaultTypes = defaultAdditionalTypes
+	}
+	opts = append([]RequestOption{AdditionalTypes(defaultTypes...)}, opts...)
 
-	if params := processOptions(opts...).urlParams.Encode(); params != "" {
-		spotifyURL += "?" + params
+	params := processOptions(opts...).urlParams
+	if err := validateAdditionalTypes(params.Get("additional_types")); err != nil {
+		return nil, err
 	}
+	return params, nil
+}
 
-	var result PlaylistItemPage
+// GetAllPlaylistItems gets every item in a playlist, paging through the
+// results of [GetPlaylistItems] until there are no more pages.  Use the [Max]
+// option to stop once a certain number of items has been collected; pages
+// of the size given by [Limit] (or 100 by default) are still requested, and
+// the items of the final page are truncated to fit.
+//
+// Supported options: [Limit], [Offset], [Market], [Fields], [AdditionalTypes], [Max].
+func (c *Client) GetAllPlaylistItems(ctx context.Context, playlistID ID, opts ...RequestOption) ([]PlaylistItem, error) {
+	o := processOptions(opts...)
+	if o.urlParams.Get("limit") == "" {
+		limit := 100
+		if o.maxItems > 0 && o.maxItems < limit {
+			limit = o.maxItems
+		}
+		opts = append(opts, Limit(limit))
+	}
 
-	err := c.get(ctx, spotifyURL, &result)
+	page, err := c.GetPlaylistItems(ctx, playlistID, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	items := make([]PlaylistItem, 0, page.Total)
+	for {
+		items = append(items, page.Items...)
+		if o.maxItems > 0 && len(items) >= o.maxItems {
+			return items[:o.maxItems], nil
+		}
+
+		err = c.NextPage(ctx, page)
+		if err == ErrNoMorePages {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// GetPlaylistTracksInRange gets the items of a playlist that were added
+// between from and to, inclusive, such as the tracks added in a particular
+// month.  Items whose added_at is missing, as it can be for very old
+// playlists, are left out.
+//
+// By default the whole playlist is paged through, since items can be
+// reordered or inserted anywhere, so their positions say nothing reliable
+// about when they were added.  For playlists that are only ever appended to,
+// the [AssumeAddedInOrder] option stops paging at the first item added after
+// to.
+//
+// A [Fields] filter must keep the added_at field of the items.
+//
+// Supported options: [Limit], [Offset], [Market], [Fields], [AdditionalTypes], [AssumeAddedInOrder].
+func (c *Client) GetPlaylistTracksInRange(ctx context.Context, playlistID ID, from, to time.Time, opts ...RequestOption) ([]PlaylistItem, error) {
+	if to.Before(from) {
+		return nil, errors.New("spotify: the end of the range is before its start")
+	}
+	o := processOptions(opts...)
+	if o.urlParams.Get("limit") == "" {
+		opts = append(opts, Limit(100))
+	}
+
+	page, err := c.GetPlaylistItems(ctx, playlistID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []PlaylistItem
+	for {
+		for _, item := range page.Items {
+			addedAt, err := time.Parse(TimestampLayout, item.AddedAt)
+			if err != nil {
+				continue
+			}
+			if addedAt.After(to) {
+				if o.addedInOrder {
+					return items, nil
+				}
+				continue
+			}
+			if !addedAt.Before(from) {
+				items = append(items, item)
+			}
+		}
+
+		err = c.NextPage(ctx, page)
+		if err == ErrNoMorePages {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// PlaylistContains reports whether the item with the given URI, such as
+// "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", is in a playlist, and if so, the
+// zero-based position of its first occurrence.  The playlist is paged
+// through from the start, requesting only the URIs of its items, and the
+// search stops at the first match.  If the item isn't found, the position is
+// -1.
+func (c *Client) PlaylistContains(ctx context.Context, playlistID ID, uri URI) (found bool, position int, err error) {
+	page, err := c.GetPlaylistItems(ctx, playlistID, Fields("items(track(uri)),next,offset,total"), Limit(100))
+	if err != nil {
+		return false, -1, err
+	}
+
+	for offset := 0; ; offset += len(page.Items) {
+		for i, item := range page.Items {
+			if item.Track.Track != nil && item.Track.Track.URI == uri ||
+				item.Track.Episode != nil && item.Track.Episode.URI == uri {
+				return true, offset + i, nil
+			}
+		}
+
+		err = c.NextPage(ctx, page)
+		if err == ErrNoMorePages {
+			return false, -1, nil
+		}
+		if err != nil {
+			return false, -1, err
+		}
+	}
+}
+
+// GetItemsForPlaylists gets every item of each of the specified playlists,
+// keyed by playlist ID.  Each playlist is paged through completely, as with
+// [GetAllPlaylistItems], with a bounded number of playlists fetched
+// concurrently.  The options apply to each playlist, so for example [Max]
+// caps the number of items per playlist.
+//
+// If some of the playlists can't be fetched, the items of the others are
+// still returned, along with a [BatchError] that identifies the playlists
+// that failed.
+//
+// Supported options: [Limit], [Market], [Fields], [AdditionalTypes], [Max].
+func (c *Client) GetItemsForPlaylists(ctx context.Context, playlistIDs []ID, opts ...RequestOption) (map[ID][]PlaylistItem, error) {
+	var mu sync.Mutex
+	result := make(map[ID][]PlaylistItem, len(playlistIDs))
+
+	errs := forEachConcurrent(ctx, len(playlistIDs), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		items, err := c.GetAllPlaylistItems(ctx, playlistIDs[i], opts...)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		result[playlistIDs[i]] = items
+		mu.Unlock()
+		return nil
+	})
+
+	return result, newBatchError(playlistIDs, errs)
 }
 
 // CreatePlaylistForUser [creates a playlist] for a Spotify user.
@@ -372,16 +926,52 @@ func (c *Client) ChangePlaylistNameAccessAndDescription(ctx context.Context, pla
 }
 
 func (c *Client) modifyPlaylist(ctx context.Context, playlistID ID, newName, newDescription string, public *bool) error {
-	body := struct {
-		Name        string `json:"name,omitempty"`
-		Public      *bool  `json:"public,omitempty"`
-		Description string `json:"description,omitempty"`
-	}{
-		newName,
-		public,
-		newDescription,
+	details := PlaylistDetails{Public: public}
+	if newName != "" {
+		details.Name = &newName
 	}
-	bodyJSON, err := json.Marshal(body)
+	if newDescription != "" {
+		details.Description = &newDescription
+	}
+	return c.putPlaylistDetails(ctx, playlistID, details)
+}
+
+// PlaylistDetails holds the details of a playlist to change with
+// [Client.UpdatePlaylistDetails].  A nil field is left out of the request
+// entirely, so the playlist keeps its current value; a non-nil field is sent
+// even if it points to an empty string or false.
+type PlaylistDetails struct {
+	Name          *string `json:"name,omitempty"`
+	Public        *bool   `json:"public,omitempty"`
+	Collaborative *bool   `json:"collaborative,omitempty"`
+	Description   *string `json:"description,omitempty"`
+}
+
+// UpdatePlaylistDetails [changes the details of a playlist] in a single
+// request, sending only the fields of details that are set.  For example, to
+// make a playlist collaborative without touching its name:
+//
+//	collaborative := true
+//	err := c.UpdatePlaylistDetails(ctx, id, PlaylistDetails{Collaborative: &collaborative})
+//
+// Spotify only allows collaborative playlists to be private.  This call
+// requires that the user has authorized the [ScopePlaylistModifyPublic] or
+// [ScopePlaylistModifyPrivate] scopes (depending on whether the playlist is
+// currently public or private).  The current user must own the playlist to
+// modify it.
+//
+// [changes the details of a playlist]: https://developer.spotify.com/documentation/web-api/reference/change-playlist-details
+func (c *Client) UpdatePlaylistDetails(ctx context.Context, playlistID ID, details PlaylistDetails) error {
+	if details == (PlaylistDetails{}) {
+		return errors.New("spotify: no playlist details to update")
+	}
+	return c.putPlaylistDetails(ctx, playlistID, details)
+}
+
+// putPlaylistDetails sends details to Spotify, even if none of them are set,
+// as the older ChangePlaylist methods always have.
+func (c *Client) putPlaylistDetails(ctx context.Context, playlistID ID, details PlaylistDetails) error {
+	bodyJSON, err := json.Marshal(details)
 	if err != nil {
 		return err
 	}
@@ -394,20 +984,124 @@ func (c *Client) modifyPlaylist(ctx context.Context, playlistID ID, newName, new
 	return c.execute(req, nil, http.StatusCreated)
 }
 
+// trackURI returns the Spotify URI of the track with the given ID.  To guard
+// against double-prefixing, id may also be a track URI already, such as
+// "spotify:track:6rqhFgbbKwnb9MLmUQDhG6", in which case it is used as is.
+// Any other URI-shaped input is rejected.
+func trackURI(id ID) (string, error) {
+	s := string(id)
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) != 3 || parts[0] != "spotify" || parts[1] != "track" {
+			return "", fmt.Errorf("spotify: %q is neither a track ID nor a track URI", s)
+		}
+		s = parts[2]
+	}
+	if s == "" {
+		return "", errors.New("spotify: empty track ID")
+	}
+	return "spotify:track:" + s, nil
+}
+
+// trackURIs is like [trackURI], for several IDs.
+func trackURIs(ids []ID) ([]string, error) {
+	uris := make([]string, len(ids))
+	for i, id := range ids {
+		uri, err := trackURI(id)
+		if err != nil {
+			return nil, err
+		}
+		uris[i] = uri
+	}
+	return uris, nil
+}
+
 // AddTracksToPlaylist [adds one or more tracks to a user's playlist].
 // This call requires [ScopePlaylistModifyPublic] or [ScopePlaylistModifyPrivate].
-// A maximum of 100 tracks can be added per call.  It returns a snapshot ID that
-// can be used to identify this version (the new version) of the playlist in
-// future requests.
+// It returns a snapshot ID that can be used to identify this version (the new
+// version) of the playlist in future requests.
+//
+// Spotify accepts at most 100 tracks per request, so larger numbers of tracks
+// are added in batches of 100, one request after the other, and the snapshot
+// ID of the final batch is returned.  Each batch is only sent once the
+// previous one has been added, so the tracks end up in the playlist in the
+// order in which they were given.  If a batch fails, the error is returned
+// along with the snapshot ID of the last batch that was added, or an empty
+// string if none was, so that the caller can resume from there.  If trackIDs
+// is empty, no request is made and the snapshot ID is empty.  The tracks are
+// appended to the end of the playlist; use [AddTracksToPlaylistOpt] to insert
+// them at a position instead.
+//
+// Track URIs such as "spotify:track:6rqhFgbbKwnb9MLmUQDhG6" are accepted in
+// place of IDs, as they are by [RemoveTracksFromPlaylist] and
+// [ReplacePlaylistTracks]; any other URI results in an error before anything
+// is added.
 //
 // [adds one or more tracks to a user's playlist]: https://developer.spotify.com/documentation/web-api/reference/add-tracks-to-playlist
 func (c *Client) AddTracksToPlaylist(ctx context.Context, playlistID ID, trackIDs ...ID) (snapshotID string, err error) {
-	uris := make([]string, len(trackIDs))
-	for i, id := range trackIDs {
-		uris[i] = fmt.Sprintf("spotify:track:%s", id)
+	return c.AddTracksToPlaylistOpt(ctx, playlistID, trackIDs, nil)
+}
+
+// AddTracksOptions contains optional parameters for
+// [Client.AddTracksToPlaylistOpt].
+type AddTracksOptions struct {
+	// Position is the zero-based position at which to insert the tracks.  If
+	// it is nil, the tracks are appended to the end of the playlist.
+	Position *int
+}
+
+// AddTracksToPlaylistOpt is like [AddTracksToPlaylist], but it accepts
+// additional options, such as the position at which to insert the tracks.
+//
+// When tracks are inserted at a position and added in more than one batch,
+// each batch is inserted directly after the previous one, so the tracks keep
+// the order in which they were given.  The returned snapshot ID identifies
+// the playlist after the final batch, and can be passed to later calls such
+// as [ReorderPlaylistTracks] to detect concurrent modifications.
+func (c *Client) AddTracksToPlaylistOpt(ctx context.Context, playlistID ID, trackIDs []ID, opt *AddTracksOptions) (snapshotID string, err error) {
+	var position *int
+	if opt != nil && opt.Position != nil {
+		if *opt.Position < 0 {
+			return "", errors.New("spotify: position can't be negative")
+		}
+		p := *opt.Position
+		position = &p
+	}
+
+	if err := c.checkChunkSize(len(trackIDs), 100); err != nil {
+		return "", err
+	}
+
+	uris, err := trackURIs(trackIDs)
+	if err != nil {
+		return "", err
+	}
+
+	for len(uris) > 0 {
+		n := len(uris)
+		if n > 100 {
+			n = 100
+		}
+		snapshot, err := c.addTrackURIs(ctx, playlistID, uris[:n], position)
+		if err != nil {
+			return snapshotID, err
+		}
+		snapshotID, uris = snapshot, uris[n:]
+		if position != nil {
+			*position += n
+		}
 	}
+	return snapshotID, nil
+}
+
+// addTrackURIs adds a single batch of at most 100 items to a playlist, at
+// position if it is not nil.
+func (c *Client) addTrackURIs(ctx context.Context, playlistID ID, uris []string, position *int) (snapshotID string, err error) {
 	m := make(map[string]interface{})
 	m["uris"] = uris
+	if position != nil {
+		m["position"] = *position
+	}
 
 	spotifyURL := fmt.Sprintf("%splaylists/%s/tracks",
 		c.baseURL, string(playlistID))
@@ -433,22 +1127,127 @@ func (c *Client) AddTracksToPlaylist(ctx context.Context, playlistID ID, trackID
 	return result.SnapshotID, nil
 }
 
+// AddAlbumToPlaylist adds every track on an album to the end of a playlist, in
+// disc and track order.  The tracks are added with [AddTracksToPlaylist], so
+// albums with more than 100 tracks are added in batches, and the snapshot ID
+// of the final batch is returned.  If there is nothing to add, the returned
+// snapshot ID is empty.
+//
+// If a [Market] option is specified (or a default market is configured with
+// [WithDefaultMarket] or [WithMarketFromUser]), the album's tracks are
+// requested for that market, and tracks that Spotify reports as unplayable
+// there are skipped.
+//
+// This call requires [ScopePlaylistModifyPublic] or [ScopePlaylistModifyPrivate].
+//
+// Supported options: [Market].
+func (c *Client) AddAlbumToPlaylist(ctx context.Context, playlistID, albumID ID, opts ...RequestOption) (snapshotID string, err error) {
+	opts, err = c.marketOptions(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	params := processOptions(opts...).urlParams
+	params.Set("limit", "50")
+	skipUnplayable := params.Get("market") != ""
+
+	page := struct {
+		basePage
+		Tracks []struct {
+			SimpleTrack
+			IsPlayable *bool `json:"is_playable"`
+		} `json:"items"`
+	}{}
+	err = c.get(ctx, fmt.Sprintf("%salbums/%s/tracks?%s", c.baseURL, albumID, params.Encode()), &page)
+	if err != nil {
+		return "", err
+	}
+
+	var tracks []SimpleTrack
+	for {
+		for _, t := range page.Tracks {
+			if skipUnplayable && t.IsPlayable != nil && !*t.IsPlayable {
+				continue
+			}
+			tracks = append(tracks, t.SimpleTrack)
+		}
+
+		err = c.NextPage(ctx, &page)
+		if err == ErrNoMorePages {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	sort.SliceStable(tracks, func(i, j int) bool {
+		if tracks[i].DiscNumber != tracks[j].DiscNumber {
+			return tracks[i].DiscNumber < tracks[j].DiscNumber
+		}
+		return tracks[i].TrackNumber < tracks[j].TrackNumber
+	})
+
+	ids := make([]ID, len(tracks))
+	for i, t := range tracks {
+		ids[i] = t.ID
+	}
+	return c.AddTracksToPlaylist(ctx, playlistID, ids...)
+}
+
+// AddArtistTopTracksToPlaylist adds an artist's top tracks in a particular
+// country to the end of a playlist, in the order returned by
+// [GetArtistsTopTracks].  Spotify only ranks top tracks within a market, so
+// the country, an [ISO 3166-1 alpha-2] country code, is required.  It returns
+// the new snapshot ID of the playlist, or an empty string if the artist has no
+// top tracks in that country.
+//
+// This call requires [ScopePlaylistModifyPublic] or [ScopePlaylistModifyPrivate].
+//
+// [ISO 3166-1 alpha-2]: https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2
+func (c *Client) AddArtistTopTracksToPlaylist(ctx context.Context, playlistID, artistID ID, country string) (snapshotID string, err error) {
+	tracks, err := c.GetArtistsTopTracks(ctx, artistID, country)
+	if err != nil {
+		return "", err
+	}
+
+	ids := make([]ID, len(tracks))
+	for i, t := range tracks {
+		ids[i] = t.ID
+	}
+	return c.AddTracksToPlaylist(ctx, playlistID, ids...)
+}
+
 // RemoveTracksFromPlaylist [removes one or more tracks from a user's playlist].
 // This call requires that the user has authorized the [ScopePlaylistModifyPublic]
 // or [ScopePlaylistModifyPrivate] scopes.
 //
 // If the track(s) occur multiple times in the specified playlist, then all occurrences
-// of the track will be removed.  If successful, the snapshot ID returned can be used to
-// identify the playlist version in future requests.
+// of the track will be removed, regardless of their position.  Each track is sent to
+// Spotify without any positions, which is what instructs the API to remove every
+// occurrence; duplicate IDs in trackIDs are collapsed into a single entry.  To remove
+// only the occurrences at particular positions, use [RemoveTracksFromPlaylistOpt].
+// If successful, the snapshot ID returned can be used to identify the playlist version
+// in future requests.
 //
 // [removes one or more tracks from a user's playlist]: https://developer.spotify.com/documentation/web-api/reference/remove-tracks-playlist
 func (c *Client) RemoveTracksFromPlaylist(ctx context.Context, playlistID ID, trackIDs ...ID) (newSnapshotID string, err error) {
-	tracks := make([]struct {
+	type track struct {
 		URI string `json:"uri"`
-	}, len(trackIDs))
-
-	for i, u := range trackIDs {
-		tracks[i].URI = fmt.Sprintf("spotify:track:%s", u)
+	}
+	uris, err := trackURIs(trackIDs)
+	if err != nil {
+		return "", err
+	}
+	tracks := make([]track, 0, len(uris))
+	seen := make(map[string]bool, len(uris))
+
+	for _, u := range uris {
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		tracks = append(tracks, track{URI: u})
 	}
 	return c.removeTracksFromPlaylist(ctx, playlistID, tracks, "")
 }
@@ -459,21 +1258,29 @@ func (c *Client) RemoveTracksFromPlaylist(ctx context.Context, playlistID ID, tr
 type TrackToRemove struct {
 	URI       string `json:"uri"`
 	Positions []int  `json:"positions"`
+
+	// err records why NewTrackToRemove couldn't make a URI of its track ID.
+	err error
 }
 
 // NewTrackToRemove returns a [TrackToRemove] with the specified
-// track ID and playlist locations.
+// track ID and playlist locations.  A track URI is also accepted in place of
+// the ID.  Anything else, such as an episode URI, is reported by
+// [RemoveTracksFromPlaylistOpt] before any request is made.
 func NewTrackToRemove(trackID string, positions []int) TrackToRemove {
+	uri, err := trackURI(ID(trackID))
 	return TrackToRemove{
-		URI:       fmt.Sprintf("spotify:track:%s", trackID),
+		URI:       uri,
 		Positions: positions,
+		err:       err,
 	}
 }
 
 // RemoveTracksFromPlaylistOpt is like [RemoveTracksFromPlaylist], but it supports
 // optional parameters that offer more fine-grained control.  Instead of deleting
 // all occurrences of a track, this function takes an index with each track URI
-// that indicates the position of the track in the playlist.
+// that indicates the position of the track in the playlist.  Occurrences of the
+// track at other positions are left untouched.
 //
 // In addition, the snapshotID parameter allows you to specify the snapshot ID
 // against which you want to make the changes.  Spotify will validate that the
@@ -488,6 +1295,11 @@ func (c *Client) RemoveTracksFromPlaylistOpt(
 	tracks []TrackToRemove,
 	snapshotID string,
 ) (newSnapshotID string, err error) {
+	for _, t := range tracks {
+		if t.err != nil {
+			return "", t.err
+		}
+	}
 	return c.removeTracksFromPlaylist(ctx, playlistID, tracks, snapshotID)
 }
 
@@ -540,12 +1352,12 @@ func (c *Client) removeTracksFromPlaylist(
 //
 // [replaces all of the tracks in a playlist]: https://developer.spotify.com/documentation/web-api/reference/reorder-or-replace-playlists-tracks
 func (c *Client) ReplacePlaylistTracks(ctx context.Context, playlistID ID, trackIDs ...ID) error {
-	trackURIs := make([]string, len(trackIDs))
-	for i, u := range trackIDs {
-		trackURIs[i] = fmt.Sprintf("spotify:track:%s", u)
+	uris, err := trackURIs(trackIDs)
+	if err != nil {
+		return err
 	}
 	spotifyURL := fmt.Sprintf("%splaylists/%s/tracks?uris=%s",
-		c.baseURL, playlistID, strings.Join(trackURIs, ","))
+		c.baseURL, playlistID, strings.Join(uris, ","))
 	req, err := http.NewRequestWithContext(ctx, "PUT", spotifyURL, nil)
 	if err != nil {
 		return err
@@ -561,36 +1373,71 @@ func (c *Client) ReplacePlaylistTracks(ctx context.Context, playlistID ID, track
 // [ScopePlaylistModifyPublic] scope.  Modifying a private playlist requires the
 // [ScopePlaylistModifyPrivate] scope.
 //
-// A maximum of 100 tracks is permited in this call.  Additional tracks must be
-// added via AddTracksToPlaylist.
+// Spotify replaces at most 100 items per request, so with more items, the
+// first 100 replace the existing items and the rest are then added in batches
+// of 100.  It returns the snapshot ID of the final request.  If adding a batch
+// fails, the playlist holds only some of the items, and the error is a
+// [PartialReplaceError] that reports how many were written, so that the
+// caller can retry the rest.
 //
 // [replaces all the items in a playlist]: https://developer.spotify.com/documentation/web-api/reference/reorder-or-replace-playlists-tracks
 func (c *Client) ReplacePlaylistItems(ctx context.Context, playlistID ID, items ...URI) (string, error) {
-	m := make(map[string]interface{})
-	m["uris"] = items
-
-	body, err := json.Marshal(m)
-	if err != nil {
+	if err := c.checkChunkSize(len(items), 100); err != nil {
 		return "", err
 	}
 
-	spotifyURL := fmt.Sprintf("%splaylists/%s/tracks", c.baseURL, playlistID)
-	req, err := http.NewRequestWithContext(ctx, "PUT", spotifyURL, bytes.NewReader(body))
+	first := items
+	if len(first) > 100 {
+		first = first[:100]
+	}
+	if first == nil {
+		first = []URI{}
+	}
+	snapshotID, err := c.PutPlaylistTracks(ctx, playlistID, PutTracksBody{URIs: first})
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	result := struct {
-		SnapshotID string `json:"snapshot_id"`
-	}{}
-
-	err = c.execute(req, &result, http.StatusCreated)
-	if err != nil {
-		return "", err
+	for written := len(first); written < len(items); {
+		n := len(items) - written
+		if n > 100 {
+			n = 100
+		}
+		uris := make([]string, n)
+		for i, item := range items[written : written+n] {
+			uris[i] = string(item)
+		}
+
+		snapshot, err := c.addTrackURIs(ctx, playlistID, uris, nil)
+		if err != nil {
+			return snapshotID, PartialReplaceError{Written: written, SnapshotID: snapshotID, Err: err}
+		}
+		snapshotID, written = snapshot, written+n
 	}
+	return snapshotID, nil
+}
 
-	return result.SnapshotID, nil
+// PartialReplaceError is returned by [ReplacePlaylistItems] when the items of
+// the playlist were replaced, but adding the items beyond the first 100
+// failed part of the way through.
+type PartialReplaceError struct {
+	// Written is the number of items, from the start of the list, that are
+	// now in the playlist.
+	Written int
+	// SnapshotID identifies the version of the playlist holding the written
+	// items.
+	SnapshotID string
+	// Err is the error that stopped the remaining items from being added.
+	Err error
+}
+
+func (e PartialReplaceError) Error() string {
+	return fmt.Sprintf("spotify: replaced playlist items only partially, after %d items: %v", e.Written, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e PartialReplaceError) Unwrap() error {
+	return e.Err
 }
 
 // UserFollowsPlaylist [checks if one or more (up to 5) users are following]
@@ -642,6 +1489,35 @@ type PlaylistReorderOptions struct {
 	SnapshotID string `json:"snapshot_id,omitempty"`
 }
 
+// SnapshotMismatchError is returned by [Client.ReorderPlaylistTracks] when it is
+// given a snapshot ID that Spotify rejects, typically because the playlist
+// has been changed by someone else since the snapshot was taken, or because
+// the snapshot ID is malformed.  Fetch the playlist again to get its current
+// snapshot ID and positions before retrying.
+type SnapshotMismatchError struct {
+	// SnapshotID is the snapshot ID that was rejected.
+	SnapshotID string
+	// Err is the [Error] returned by Spotify.
+	Err error
+}
+
+func (e SnapshotMismatchError) Error() string {
+	return fmt.Sprintf("spotify: playlist snapshot %q doesn't match: %v", e.SnapshotID, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e SnapshotMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// isSnapshotMismatch reports whether err is Spotify's response to a request
+// made against a snapshot ID that it doesn't accept.  Spotify reports a stale
+// or unknown snapshot as a bad request whose message mentions the snapshot.
+func isSnapshotMismatch(err error) bool {
+	e, ok := err.(Error)
+	return ok && e.Status == http.StatusBadRequest && strings.Contains(strings.ToLower(e.Message), "snapshot")
+}
+
 // ReorderPlaylistTracks reorders a track or group of tracks in a playlist.  It
 // returns a snapshot ID that can be used to identify the (newly modified) playlist
 // version in future requests.
@@ -649,15 +1525,70 @@ type PlaylistReorderOptions struct {
 // See the docs for [PlaylistReorderOptions] for information on how the reordering
 // works.
 //
+// To make several reorders in a row, pass the snapshot ID returned by each
+// call as the SnapshotID of the next, so that Spotify interprets the positions
+// against the playlist as the previous call left it.  If Spotify rejects the
+// snapshot ID, a [SnapshotMismatchError] is returned and the playlist is left
+// unchanged.
+//
 // Reordering tracks in the current user's public playlist requires [ScopePlaylistModifyPublic].
 // Reordering tracks in the user's private playlists (including collaborative playlists) requires
 // [ScopePlaylistModifyPrivate].
 func (c *Client) ReorderPlaylistTracks(ctx context.Context, playlistID ID, opt PlaylistReorderOptions) (snapshotID string, err error) {
-	spotifyURL := fmt.Sprintf("%splaylists/%s/tracks", c.baseURL, playlistID)
-	j, err := json.Marshal(opt)
+	snapshotID, err = c.PutPlaylistTracks(ctx, playlistID, PutTracksBody{Reorder: &opt})
+	if err != nil && opt.SnapshotID != "" && isSnapshotMismatch(err) {
+		return "", SnapshotMismatchError{SnapshotID: opt.SnapshotID, Err: err}
+	}
+	return snapshotID, err
+}
+
+// ErrConflictingBody is returned by [PutPlaylistTracks] when a [PutTracksBody]
+// specifies both a replacement and a reorder.
+var ErrConflictingBody = errors.New("spotify: can't both replace and reorder playlist items in one request")
+
+// PutTracksBody is the body of a request that either replaces all of the items
+// in a playlist or reorders them.  Spotify's endpoint accepts one or the other,
+// so exactly one of the fields must be set.
+type PutTracksBody struct {
+	// URIs replaces the items in the playlist with the given items.  A
+	// non-nil, empty slice clears the playlist.  A maximum of 100 items can
+	// be set this way.
+	URIs []URI
+	// Reorder moves a range of items within the playlist.  See
+	// [PlaylistReorderOptions] for how the reordering works.
+	Reorder *PlaylistReorderOptions
+}
+
+// PutPlaylistTracks [replaces or reorders] the items in a playlist, as
+// described by body.  It returns [ErrConflictingBody] if body specifies both a
+// replacement and a reorder.  On success, it returns a snapshot ID that can be
+// used to identify the (newly modified) playlist version in future requests.
+// [ReplacePlaylistItems] and [ReorderPlaylistTracks] are built on top of it.
+//
+// Modifying a public playlist requires that the user has authorized the
+// [ScopePlaylistModifyPublic] scope.  Modifying a private playlist (including
+// a collaborative playlist) requires the [ScopePlaylistModifyPrivate] scope.
+//
+// [replaces or reorders]: https://developer.spotify.com/documentation/web-api/reference/reorder-or-replace-playlists-tracks
+func (c *Client) PutPlaylistTracks(ctx context.Context, playlistID ID, body PutTracksBody) (snapshotID string, err error) {
+	var payload interface{}
+	switch {
+	case body.URIs != nil && body.Reorder != nil:
+		return "", ErrConflictingBody
+	case body.URIs != nil:
+		payload = map[string][]URI{"uris": body.URIs}
+	case body.Reorder != nil:
+		payload = body.Reorder
+	default:
+		return "", errors.New("spotify: either URIs or a reorder must be specified")
+	}
+
+	j, err := json.Marshal(payload)
 	if err != nil {
 		return "", err
 	}
+
+	spotifyURL := fmt.Sprintf("%splaylists/%s/tracks", c.baseURL, playlistID)
 	req, err := http.NewRequestWithContext(ctx, "PUT", spotifyURL, bytes.NewReader(j))
 	if err != nil {
 		return "", err
@@ -667,7 +1598,7 @@ func (c *Client) ReorderPlaylistTracks(ctx context.Context, playlistID ID, opt P
 	result := struct {
 		SnapshotID string `json:"snapshot_id"`
 	}{}
-	err = c.execute(req, &result)
+	err = c.execute(req, &result, http.StatusCreated)
 	if err != nil {
 		return "", err
 	}
@@ -675,24 +1606,90 @@ func (c *Client) ReorderPlaylistTracks(ctx context.Context, playlistID ID, opt P
 	return result.SnapshotID, nil
 }
 
+// GetPlaylistImages [gets the cover images] of a playlist.  Spotify may return
+// several sizes of the same image; the height and width of an image are zero
+// when Spotify doesn't report them, as is the case for images that were
+// uploaded with [SetPlaylistImage].  The returned URLs are temporary and
+// expire within a day.
+//
+// [gets the cover images]: https://developer.spotify.com/documentation/web-api/reference/get-playlist-cover
+func (c *Client) GetPlaylistImages(ctx context.Context, playlistID ID) ([]Image, error) {
+	spotifyURL := fmt.Sprintf("%splaylists/%s/images", c.baseURL, playlistID)
+
+	var result []Image
+
+	err := c.get(ctx, spotifyURL, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MaxPlaylistImageSize is the largest payload, in bytes, that Spotify accepts
+// when setting a playlist's image.  The payload is the base64 encoding of the
+// image, so the largest image that can be uploaded is three quarters of this
+// size, or 192 KB.
+const MaxPlaylistImageSize = 256 * 1024
+
+var (
+	// ErrImageTooLarge is returned by [Client.SetPlaylistImage] and
+	// [Client.SetPlaylistImageBase64] when the encoded image is larger than
+	// [MaxPlaylistImageSize].
+	ErrImageTooLarge = fmt.Errorf("spotify: playlist images must be at most %d bytes once base64 encoded", MaxPlaylistImageSize)
+	// ErrImageNotJPEG is returned by [Client.SetPlaylistImage] and
+	// [Client.SetPlaylistImageBase64] when the image isn't a JPEG, which is
+	// the only format Spotify accepts.
+	ErrImageNotJPEG = errors.New("spotify: playlist images must be JPEG")
+)
+
 // SetPlaylistImage replaces the image used to represent a playlist.
 // This action can only be performed by the owner of the playlist,
 // and requires [ScopeImageUpload] as well as [ScopeModifyPlaylistPublic] or
 // [ScopeModifyPlaylistPrivate].
+//
+// The image is read from img and base64 encoded before it is sent.  It must
+// be a JPEG, of at most 192 KB so that its encoding fits in
+// [MaxPlaylistImageSize]; otherwise [ErrImageNotJPEG] or [ErrImageTooLarge]
+// is returned without making a request.
 func (c *Client) SetPlaylistImage(ctx context.Context, playlistID ID, img io.Reader) error {
+	// read one byte more than the largest image that fits, to detect
+	// larger images without reading all of them
+	maxSize := base64.StdEncoding.DecodedLen(MaxPlaylistImageSize)
+	data, err := io.ReadAll(io.LimitReader(img, int64(maxSize)+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxSize {
+		return ErrImageTooLarge
+	}
+	if http.DetectContentType(data) != "image/jpeg" {
+		return ErrImageNotJPEG
+	}
+	return c.putPlaylistImage(ctx, playlistID, base64.StdEncoding.EncodeToString(data))
+}
+
+// SetPlaylistImageBase64 is like [Client.SetPlaylistImage], but takes an image
+// that is already base64 encoded, using the standard encoding with padding.
+// The encoded image is checked the same way before it is sent.
+func (c *Client) SetPlaylistImageBase64(ctx context.Context, playlistID ID, encoded string) error {
+	if len(encoded) > MaxPlaylistImageSize {
+		return ErrImageTooLarge
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("spotify: invalid base64 image: %w", err)
+	}
+	if http.DetectContentType(data) != "image/jpeg" {
+		return ErrImageNotJPEG
+	}
+	return c.putPlaylistImage(ctx, playlistID, encoded)
+}
+
+// putPlaylistImage sends an image that has already been checked and encoded.
+func (c *Client) putPlaylistImage(ctx context.Context, playlistID ID, encoded string) error {
 	spotifyURL := fmt.Sprintf("%splaylists/%s/images", c.baseURL, playlistID)
-	// data flow:
-	// img (reader) -> copy into base64 encoder (writer) -> pipe (write end)
-	// pipe (read end) -> request body
-	r, w := io.Pipe()
-	go func() {
-		enc := base64.NewEncoder(base64.StdEncoding, w)
-		_, err := io.Copy(enc, img)
-		_ = enc.Close()
-		_ = w.CloseWithError(err)
-	}()
-
-	req, err := http.NewRequestWithContext(ctx, "PUT", spotifyURL, r)
+	req, err := http.NewRequestWithContext(ctx, "PUT", spotifyURL, strings.NewReader(encoded))
 	if err != nil {
 		return err
 	}
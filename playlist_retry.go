@@ -0,0 +1,149 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// maxAddAttempts is the number of times [Client.RetrySafeAddTracks] tries to
+// add each batch of tracks.
+const maxAddAttempts = 3
+
+// playlistStateFields selects just what [Client.RetrySafeAddTracks] needs to
+// tell whether a playlist has changed.
+var playlistStateFields = NewFields("snapshot_id").Nested("tracks", NewFields("total")).String()
+
+// RetrySafeAddTracks appends tracks to a playlist like
+// [Client.AddTracksToPlaylist], but retries batches whose outcome is unknown
+// without adding them twice.
+//
+// Adding tracks isn't idempotent: if a request times out or fails with a
+// server error, the tracks may have been added anyway, and simply sending
+// the request again would add them a second time.  So before retrying a
+// batch, RetrySafeAddTracks fetches the playlist's snapshot ID.  If it hasn't
+// changed, the batch wasn't added and is sent again.  If it has, the items
+// at the end of the playlist, where the batch would have been appended, are
+// compared with the batch, and the batch is only sent again if they don't
+// match.  Each batch is tried up to three times.  Errors that show the
+// request was rejected, such as a 403, are returned straight away.
+//
+// The check costs an extra request for the snapshot ID before the first
+// batch, and a few more for each failed attempt.  It relies on the playlist
+// not being changed by anyone else in the meantime: if someone else adds
+// the same tracks, or removes items before the end of the playlist, while a
+// batch is in doubt, the batch may be wrongly considered added or added
+// twice.  Use [Client.AddTracksToPlaylist] when duplicates don't matter.
+//
+// As with [Client.AddTracksToPlaylist], the snapshot ID of the last batch
+// that was added is returned, along with the error if a batch couldn't be
+// added.
+func (c *Client) RetrySafeAddTracks(ctx context.Context, playlistID ID, trackIDs ...ID) (snapshotID string, err error) {
+	if err := c.checkChunkSize(len(trackIDs), 100); err != nil {
+		return "", err
+	}
+	uris, err := trackURIs(trackIDs)
+	if err != nil {
+		return "", err
+	}
+	if len(uris) == 0 {
+		return "", nil
+	}
+
+	before, err := c.GetPlaylist(ctx, playlistID, Fields(playlistStateFields))
+	if err != nil {
+		return "", err
+	}
+	total := int(before.Tracks.Total)
+	lastSnapshot := before.SnapshotID
+
+	for len(uris) > 0 {
+		n := len(uris)
+		if n > 100 {
+			n = 100
+		}
+		snapshot, err := c.addBatchOnce(ctx, playlistID, uris[:n], lastSnapshot, total)
+		if err != nil {
+			return snapshotID, err
+		}
+		snapshotID, lastSnapshot, uris, total = snapshot, snapshot, uris[n:], total+n
+	}
+	return snapshotID, nil
+}
+
+// addBatchOnce appends a batch of tracks to a playlist whose snapshot ID and
+// number of items were lastSnapshot and total before the batch, making sure
+// that the batch is added exactly once.
+func (c *Client) addBatchOnce(ctx context.Context, playlistID ID, uris []string, lastSnapshot string, total int) (string, error) {
+	var err error
+	for attempt := 0; attempt < maxAddAttempts; attempt++ {
+		if attempt > 0 {
+			added, snapshot, checkErr := c.batchWasAdded(ctx, playlistID, uris, lastSnapshot, total)
+			if checkErr != nil {
+				return "", checkErr
+			}
+			if added {
+				return snapshot, nil
+			}
+		}
+
+		var snapshot string
+		snapshot, err = c.addTrackURIs(ctx, playlistID, uris, nil)
+		if err == nil {
+			return snapshot, nil
+		}
+		if !isAmbiguousFailure(ctx, err) {
+			return "", err
+		}
+	}
+	return "", err
+}
+
+// batchWasAdded reports whether a batch of tracks whose outcome is unknown
+// was appended to a playlist, and if so, returns the playlist's current
+// snapshot ID.
+func (c *Client) batchWasAdded(ctx context.Context, playlistID ID, uris []string, lastSnapshot string, total int) (bool, string, error) {
+	current, err := c.GetPlaylist(ctx, playlistID, Fields(playlistStateFields))
+	if err != nil {
+		return false, "", err
+	}
+	if current.SnapshotID == lastSnapshot || int(current.Tracks.Total) < total+len(uris) {
+		return false, "", nil
+	}
+
+	page, err := c.GetPlaylistItems(ctx, playlistID, Offset(total), Limit(len(uris)), Fields("items(track(uri))"))
+	if err != nil {
+		return false, "", err
+	}
+	if len(page.Items) != len(uris) {
+		return false, "", nil
+	}
+	for i, item := range page.Items {
+		var uri URI
+		switch {
+		case item.Track.Track != nil:
+			uri = item.Track.Track.URI
+		case item.Track.Episode != nil:
+			uri = item.Track.Episode.URI
+		}
+		if string(uri) != uris[i] {
+			return false, "", nil
+		}
+	}
+	return true, current.SnapshotID, nil
+}
+
+// isAmbiguousFailure reports whether err leaves it unknown whether a request
+// was carried out: a network error, such as a timeout, or a server error.
+// Errors that show the request was rejected aren't ambiguous, and nor is the
+// cancellation of ctx, which means the caller has given up.
+func isAmbiguousFailure(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var e Error
+	if errors.As(err, &e) {
+		return e.Status >= http.StatusInternalServerError
+	}
+	return true
+}
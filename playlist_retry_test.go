@@ -0,0 +1,131 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// flakyPlaylist serves a playlist whose additions can be made to fail, either
+// after the tracks have been added (dropping the connection, as if the
+// response timed out) or before.
+type flakyPlaylist struct {
+	t        *testing.T
+	items    []string
+	snapshot int
+	posts    int
+	// failures holds, for each POST in turn, whether it should apply the
+	// addition and then drop the connection ("phantom"), fail without
+	// applying it ("fail"), or succeed ("").
+	failures []string
+}
+
+func (p *flakyPlaylist) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/playlists/playlist_id":
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d", "tracks": { "total": %d } }`, p.snapshot, len(p.items))
+	case r.Method == http.MethodGet && r.URL.Path == "/playlists/playlist_id/tracks":
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var items []string
+		for i := offset; i < offset+limit && i < len(p.items); i++ {
+			items = append(items, fmt.Sprintf(`{ "track": { "uri": "%s" } }`, p.items[i]))
+		}
+		fmt.Fprintf(w, `{ "items": [ %s ] }`, strings.Join(items, ","))
+	case r.Method == http.MethodPost:
+		failure := ""
+		if p.posts < len(p.failures) {
+			failure = p.failures[p.posts]
+		}
+		p.posts++
+		if failure == "fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			p.t.Error("Error decoding request body:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		p.items = append(p.items, body.URIs...)
+		p.snapshot++
+
+		if failure == "phantom" {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				p.t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d" }`, p.snapshot)
+	default:
+		p.t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+	}
+}
+
+func TestRetrySafeAddTracks(t *testing.T) {
+	ids := make([]ID, 150)
+	want := []string{"spotify:track:existing"}
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", i))
+		want = append(want, "spotify:track:"+string(ids[i]))
+	}
+
+	testTable := []struct {
+		name     string
+		failures []string
+		posts    int
+	}{
+		{"no failures", nil, 2},
+		{"added then timed out", []string{"phantom"}, 2},
+		{"failed then retried", []string{"fail", "", "fail"}, 4},
+		{"timed out in the second batch", []string{"", "phantom"}, 2},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist := &flakyPlaylist{t: t, items: []string{"spotify:track:existing"}, failures: tt.failures}
+			client, server := testClientHandler(playlist)
+			defer server.Close()
+
+			snapshot, err := client.RetrySafeAddTracks(context.Background(), "playlist_id", ids...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(playlist.items, want) {
+				t.Errorf("Expected each track to be added once, got %d items", len(playlist.items))
+			}
+			if playlist.posts != tt.posts {
+				t.Errorf("Expected %d additions, got %d", tt.posts, playlist.posts)
+			}
+			if want := fmt.Sprintf("snapshot%d", playlist.snapshot); snapshot != want {
+				t.Errorf("Expected snapshot %s, got %s", want, snapshot)
+			}
+		})
+	}
+}
+
+func TestRetrySafeAddTracksGivesUp(t *testing.T) {
+	playlist := &flakyPlaylist{t: t, failures: []string{"fail", "fail", "fail"}}
+	client, server := testClientHandler(playlist)
+	defer server.Close()
+
+	_, err := client.RetrySafeAddTracks(context.Background(), "playlist_id", "track")
+	if e, ok := err.(Error); !ok || e.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected a 503 error, got %v", err)
+	}
+	if playlist.posts != maxAddAttempts || len(playlist.items) != 0 {
+		t.Errorf("Expected %d attempts and no items, got %d attempts and %d items", maxAddAttempts, playlist.posts, len(playlist.items))
+	}
+}
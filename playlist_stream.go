@@ -0,0 +1,117 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrStopStreaming can be returned by the function given to
+// [Client.StreamPlaylistItems] to stop streaming without an error.
+var ErrStopStreaming = errors.New("spotify: stop streaming")
+
+// StreamPlaylistItems calls fn for each item of a playlist, in order, paging
+// through the playlist until there are no more pages.  Unlike
+// [GetAllPlaylistItems], the items are decoded one at a time as each response
+// is read, and aren't kept after fn returns, so the memory used stays bounded
+// however large the playlist is.
+//
+// If fn returns an error, streaming stops and the error is returned, unless it
+// is [ErrStopStreaming], in which case nil is returned.
+//
+// Supported options: [Limit], [Offset], [Market], [AdditionalTypes].
+func (c *Client) StreamPlaylistItems(ctx context.Context, playlistID ID, fn func(PlaylistItem) error, opts ...RequestOption) error {
+	if processOptions(opts...).urlParams.Get("limit") == "" {
+		opts = append(opts, Limit(100))
+	}
+	spotifyURL, err := c.playlistItemsURL(ctx, playlistID, opts)
+	if err != nil {
+		return err
+	}
+
+	for spotifyURL != "" {
+		var next string
+		err := c.getStream(ctx, spotifyURL, func(body io.Reader) error {
+			var err error
+			next, err = decodePlaylistItemsStream(body, fn)
+			return err
+		})
+		if err == ErrStopStreaming {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if next == "" {
+			return nil
+		}
+		if spotifyURL, err = c.pageURL(next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodePlaylistItemsStream reads a page of playlist items from r, calling
+// fn for each item as it is decoded, and returns the URL of the next page.
+// Fields other than "items" and "next" are skipped.
+func decodePlaylistItemsStream(r io.Reader, fn func(PlaylistItem) error) (next string, err error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case "items":
+			if err := expectDelim(dec, '['); err != nil {
+				return "", err
+			}
+			for dec.More() {
+				var item PlaylistItem
+				if err := dec.Decode(&item); err != nil {
+					return "", err
+				}
+				if err := fn(item); err != nil {
+					return "", err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return "", err
+			}
+		case "next":
+			var s *string
+			if err := dec.Decode(&s); err != nil {
+				return "", err
+			}
+			if s != nil {
+				next = *s
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return "", err
+			}
+		}
+	}
+	return next, expectDelim(dec, '}')
+}
+
+// expectDelim reads the next token from dec and returns an error if it isn't
+// the delimiter d.
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != d {
+		return fmt.Errorf("spotify: expected %v in the response, got %v", d, t)
+	}
+	return nil
+}
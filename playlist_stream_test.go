@@ -0,0 +1,92 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStreamPlaylistItems(t *testing.T) {
+	var pages int
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		query := r.URL.Query()
+		if query.Get("limit") != "2" || query.Get("additional_types") != "episode,track" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		var items []string
+		for i := offset; i < offset+2 && i < 5; i++ {
+			if i == 3 {
+				items = append(items, `{ "added_at": "2023-01-01T00:00:00Z", "track": { "type": "episode", "id": "episode3" } }`)
+				continue
+			}
+			items = append(items, fmt.Sprintf(`{ "track": { "type": "track", "id": "track%d", "album": { "name": "Album" } } }`, i))
+		}
+		next := "null"
+		if offset+2 < 5 {
+			query.Set("offset", strconv.Itoa(offset+2))
+			next = fmt.Sprintf(`"http://%s/playlists/playlist_id/tracks?%s"`, r.Host, query.Encode())
+		}
+		fmt.Fprintf(w, `{ "href": "ignored", "items": [ %s ], "limit": 2, "next": %s, "offset": %d, "total": 5 }`,
+			strings.Join(items, ","), next, offset)
+	}))
+	defer server.Close()
+
+	var ids []string
+	err := client.StreamPlaylistItems(context.Background(), "playlist_id", func(item PlaylistItem) error {
+		switch {
+		case item.Track.Track != nil:
+			ids = append(ids, string(item.Track.Track.ID))
+		case item.Track.Episode != nil:
+			ids = append(ids, string(item.Track.Episode.ID)+"@"+item.AddedAt)
+		}
+		return nil
+	}, Limit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(ids, ","); got != "track0,track1,track2,episode3@2023-01-01T00:00:00Z,track4" {
+		t.Errorf("Unexpected items %s", got)
+	}
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+
+	pages, ids = 0, nil
+	err = client.StreamPlaylistItems(context.Background(), "playlist_id", func(item PlaylistItem) error {
+		ids = append(ids, string(item.Track.Track.ID))
+		if len(ids) == 3 {
+			return ErrStopStreaming
+		}
+		return nil
+	}, Limit(2))
+	if err != nil || len(ids) != 3 || pages != 2 {
+		t.Errorf("Expected to stop after 3 items on the second page, got %d items on %d pages (%v)", len(ids), pages, err)
+	}
+
+	errFailed := errors.New("failed")
+	err = client.StreamPlaylistItems(context.Background(), "playlist_id", func(PlaylistItem) error {
+		return errFailed
+	}, Limit(2))
+	if err != errFailed {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+}
+
+func TestStreamPlaylistItemsError(t *testing.T) {
+	client, server := testClientString(http.StatusNotFound, `{ "error": { "status": 404, "message": "Not found" } }`)
+	defer server.Close()
+
+	err := client.StreamPlaylistItems(context.Background(), "playlist_id", func(PlaylistItem) error {
+		t.Error("Unexpected item")
+		return nil
+	})
+	if e, ok := err.(Error); !ok || e.Status != http.StatusNotFound {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// PlaylistOpKind is the kind of a [PlaylistOp].
+type PlaylistOpKind int
+
+// The kinds of [PlaylistOp].
+const (
+	// PlaylistOpRemove removes the item at Position.
+	PlaylistOpRemove PlaylistOpKind = iota
+	// PlaylistOpReorder moves the item at Position to before the item at
+	// InsertBefore, as in [PlaylistReorderOptions].
+	PlaylistOpReorder
+	// PlaylistOpAdd inserts URIs at Position.
+	PlaylistOpAdd
+)
+
+func (k PlaylistOpKind) String() string {
+	switch k {
+	case PlaylistOpRemove:
+		return "remove"
+	case PlaylistOpReorder:
+		return "reorder"
+	case PlaylistOpAdd:
+		return "add"
+	default:
+		return "unknown"
+	}
+}
+
+// PlaylistOp is a single step of a plan made by [PlanPlaylistSync].
+// Positions are zero-based, and refer to the playlist as it is after all of
+// the previous steps of the plan have been applied.
+type PlaylistOp struct {
+	Kind PlaylistOpKind
+	// URIs holds the items to add for [PlaylistOpAdd], and the item at
+	// Position for the other kinds.
+	URIs []URI
+	// Position is the position of the item to remove or move, or the
+	// position at which to add the items.
+	Position int
+	// InsertBefore is the position before which to move the item, for
+	// [PlaylistOpReorder] only.
+	InsertBefore int
+}
+
+// PlanPlaylistSync plans how to turn a playlist with the items current into
+// one with the items target, changing as little as possible.  Items that are
+// in both lists are kept rather than removed and added again, so they keep
+// their added_at and added_by details.  The plan first removes the items that
+// aren't in target, then moves the fewest possible kept items into place, and
+// finally adds the missing items, in runs of consecutive items.  Use
+// [Client.ApplyPlaylistPlan] to carry it out.
+//
+// Duplicate items are matched up in order.  If current and target are the
+// same, the plan is empty.
+func PlanPlaylistSync(current, target []URI) []PlaylistOp {
+	var plan []PlaylistOp
+
+	// match up the items to keep with their positions in the target
+	targetPositions := make(map[URI][]int)
+	for i, uri := range target {
+		targetPositions[uri] = append(targetPositions[uri], i)
+	}
+	kept := make([]bool, len(target))
+	var work []int // the target positions of the remaining items, in order
+	var removed []int
+	for i, uri := range current {
+		if positions := targetPositions[uri]; len(positions) > 0 {
+			work = append(work, positions[0])
+			kept[positions[0]] = true
+			targetPositions[uri] = positions[1:]
+		} else {
+			removed = append(removed, i)
+		}
+	}
+
+	// remove from the end, so that the positions of the other items to
+	// remove stay the same
+	for i := len(removed) - 1; i >= 0; i-- {
+		plan = append(plan, PlaylistOp{
+			Kind:     PlaylistOpRemove,
+			URIs:     []URI{current[removed[i]]},
+			Position: removed[i],
+		})
+	}
+
+	// the longest run of items that are already in order stays in place,
+	// and the others are moved in between them
+	placed := make(map[int]bool, len(work))
+	for _, t := range longestIncreasingSubsequence(work) {
+		placed[t] = true
+	}
+	toMove := make([]int, 0, len(work)-len(placed))
+	for _, t := range work {
+		if !placed[t] {
+			toMove = append(toMove, t)
+		}
+	}
+	sort.Ints(toMove)
+	for _, t := range toMove {
+		from, before := -1, len(work)
+		for i, w := range work {
+			if w == t {
+				from = i
+			}
+			if placed[w] && w > t && before == len(work) {
+				before = i
+			}
+		}
+		plan = append(plan, PlaylistOp{
+			Kind:         PlaylistOpReorder,
+			URIs:         []URI{target[t]},
+			Position:     from,
+			InsertBefore: before,
+		})
+
+		work = append(work[:from], work[from+1:]...)
+		if before > from {
+			before--
+		}
+		work = append(work[:before], append([]int{t}, work[before:]...)...)
+		placed[t] = true
+	}
+
+	// the kept items are now in the order of the target, so the missing
+	// items can be added where they belong
+	for i := 0; i < len(target); i++ {
+		if kept[i] {
+			continue
+		}
+		start := i
+		for i < len(target) && !kept[i] {
+			i++
+		}
+		plan = append(plan, PlaylistOp{
+			Kind:     PlaylistOpAdd,
+			URIs:     target[start:i:i],
+			Position: start,
+		})
+	}
+
+	return plan
+}
+
+// longestIncreasingSubsequence returns the values of a longest strictly
+// increasing subsequence of s.
+func longestIncreasingSubsequence(s []int) []int {
+	// tails[k] is the index in s of the smallest value that ends an
+	// increasing subsequence of length k+1
+	var tails []int
+	prev := make([]int, len(s))
+	for i, v := range s {
+		k := sort.Search(len(tails), func(k int) bool { return s[tails[k]] >= v })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	result := make([]int, len(tails))
+	if len(tails) == 0 {
+		return result
+	}
+	for i, k := tails[len(tails)-1], len(tails)-1; k >= 0; i, k = prev[i], k-1 {
+		result[k] = s[i]
+	}
+	return result
+}
+
+// ApplyPlaylistPlan carries out a plan made by [PlanPlaylistSync] on a
+// playlist, and returns the snapshot ID of the playlist after the last step.
+// The snapshot ID returned by each step is passed to the next step that
+// supports one, so that Spotify interprets its positions against the
+// playlist as the previous step left it.  Consecutive removals and additions
+// are made in as few requests as possible.
+//
+// If a step fails, the steps before it have been applied, and the snapshot ID
+// of the last step that was applied is returned along with the error.
+//
+// This call requires [ScopePlaylistModifyPublic] or [ScopePlaylistModifyPrivate].
+func (c *Client) ApplyPlaylistPlan(ctx context.Context, playlistID ID, plan []PlaylistOp) (snapshotID string, err error) {
+	for i := 0; i < len(plan); i++ {
+		op := plan[i]
+		var snapshot string
+		switch op.Kind {
+		case PlaylistOpRemove:
+			// gather up to 100 consecutive removals, which are planned
+			// from the end, so that their positions all refer to the
+			// playlist before any of them
+			var tracks []TrackToRemove
+			index := make(map[URI]int)
+			for n := 0; n < 100 && i < len(plan) && plan[i].Kind == PlaylistOpRemove; n, i = n+1, i+1 {
+				uri := plan[i].URIs[0]
+				if _, ok := index[uri]; !ok {
+					index[uri] = len(tracks)
+					tracks = append(tracks, TrackToRemove{URI: string(uri)})
+				}
+				tracks[index[uri]].Positions = append(tracks[index[uri]].Positions, plan[i].Position)
+			}
+			i--
+			snapshot, err = c.removeTracksFromPlaylist(ctx, playlistID, tracks, snapshotID)
+		case PlaylistOpReorder:
+			snapshot, err = c.ReorderPlaylistTracks(ctx, playlistID, PlaylistReorderOptions{
+				RangeStart:   Numeric(op.Position),
+				RangeLength:  1,
+				InsertBefore: Numeric(op.InsertBefore),
+				SnapshotID:   snapshotID,
+			})
+		case PlaylistOpAdd:
+			uris := make([]string, len(op.URIs))
+			for j, uri := range op.URIs {
+				uris[j] = string(uri)
+			}
+			position := op.Position
+			for len(uris) > 0 {
+				n := len(uris)
+				if n > 100 {
+					n = 100
+				}
+				snapshot, err = c.addTrackURIs(ctx, playlistID, uris[:n], &position)
+				if err != nil {
+					break
+				}
+				snapshotID, uris, position = snapshot, uris[n:], position+n
+			}
+		default:
+			return snapshotID, fmt.Errorf("spotify: unknown playlist operation %d", op.Kind)
+		}
+		if err != nil {
+			return snapshotID, err
+		}
+		snapshotID = snapshot
+	}
+	return snapshotID, nil
+}
@@ -0,0 +1,171 @@
+package spotify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// applyPlaylistOps applies plan to items the way Spotify would.
+func applyPlaylistOps(t *testing.T, items []URI, plan []PlaylistOp) []URI {
+	items = append([]URI(nil), items...)
+	for _, op := range plan {
+		switch op.Kind {
+		case PlaylistOpRemove:
+			if items[op.Position] != op.URIs[0] {
+				t.Fatalf("Removing %s at %d, but found %s", op.URIs[0], op.Position, items[op.Position])
+			}
+			items = append(items[:op.Position], items[op.Position+1:]...)
+		case PlaylistOpReorder:
+			if items[op.Position] != op.URIs[0] {
+				t.Fatalf("Moving %s at %d, but found %s", op.URIs[0], op.Position, items[op.Position])
+			}
+			items = moveItem(items, op.Position, op.InsertBefore)
+		case PlaylistOpAdd:
+			items = append(items[:op.Position], append(append([]URI(nil), op.URIs...), items[op.Position:]...)...)
+		}
+	}
+	return items
+}
+
+func moveItem(items []URI, from, before int) []URI {
+	item := items[from]
+	items = append(items[:from], items[from+1:]...)
+	if before > from {
+		before--
+	}
+	return append(items[:before], append([]URI{item}, items[before:]...)...)
+}
+
+func testURIs(s string) []URI {
+	if s == "" {
+		return nil
+	}
+	var result []URI
+	for _, id := range strings.Split(s, " ") {
+		result = append(result, URI("spotify:track:"+id))
+	}
+	return result
+}
+
+func TestPlanPlaylistSync(t *testing.T) {
+	testTable := []struct {
+		Current, Target string
+		Removes, Moves  int
+		Adds            int
+	}{
+		{"a b c", "a b c", 0, 0, 0},
+		{"", "a b c", 0, 0, 1},
+		{"a b c", "", 3, 0, 0},
+		{"a b c", "c a b", 0, 1, 0},
+		{"a b c d", "b c d a", 0, 1, 0},
+		{"a b c d", "d c b a", 0, 3, 0},
+		{"a x b y c", "a b c", 2, 0, 0},
+		{"a b c", "z a y y b c w", 0, 0, 3},
+		{"a a b", "b a", 1, 1, 0},
+		{"a b x c", "c n b a m", 1, 2, 2},
+	}
+
+	for _, tt := range testTable {
+		current, target := testURIs(tt.Current), testURIs(tt.Target)
+		plan := PlanPlaylistSync(current, target)
+		counts := map[PlaylistOpKind]int{}
+		for _, op := range plan {
+			counts[op.Kind]++
+		}
+		if counts[PlaylistOpRemove] != tt.Removes || counts[PlaylistOpReorder] != tt.Moves || counts[PlaylistOpAdd] != tt.Adds {
+			t.Errorf("%q to %q: got %d removes, %d moves and %d adds, expected %d, %d and %d", tt.Current, tt.Target,
+				counts[PlaylistOpRemove], counts[PlaylistOpReorder], counts[PlaylistOpAdd], tt.Removes, tt.Moves, tt.Adds)
+		}
+		if got := applyPlaylistOps(t, current, plan); !reflect.DeepEqual(got, target) && len(got)+len(target) != 0 {
+			t.Errorf("%q to %q: got %v", tt.Current, tt.Target, got)
+		}
+	}
+}
+
+func TestPlanPlaylistSyncRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomItems := func() []URI {
+		items := make([]URI, r.Intn(12))
+		for i := range items {
+			items[i] = URI(fmt.Sprintf("spotify:track:%c", 'a'+r.Intn(8)))
+		}
+		return items
+	}
+	for i := 0; i < 500; i++ {
+		current, target := randomItems(), randomItems()
+		plan := PlanPlaylistSync(current, target)
+		if got := applyPlaylistOps(t, current, plan); !reflect.DeepEqual(got, target) && len(got)+len(target) != 0 {
+			t.Fatalf("%v to %v: got %v with plan %+v", current, target, got, plan)
+		}
+	}
+}
+
+func TestApplyPlaylistPlan(t *testing.T) {
+	items := testURIs("a b x c")
+	target := testURIs("c n b a m")
+	snapshot := 0
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			URIs         []URI           `json:"uris"`
+			Position     *int            `json:"position"`
+			Tracks       []TrackToRemove `json:"tracks"`
+			SnapshotID   string          `json:"snapshot_id"`
+			RangeStart   int             `json:"range_start"`
+			InsertBefore int             `json:"insert_before"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		if body.SnapshotID != "" && body.SnapshotID != fmt.Sprintf("snapshot%d", snapshot) {
+			t.Errorf("Got snapshot %s, expected snapshot%d", body.SnapshotID, snapshot)
+		}
+
+		switch r.Method {
+		case http.MethodDelete:
+			var positions []int
+			for _, track := range body.Tracks {
+				for _, p := range track.Positions {
+					if items[p] != URI(track.URI) {
+						t.Errorf("Removing %s at %d, but found %s", track.URI, p, items[p])
+					}
+					positions = append(positions, p)
+				}
+			}
+			sort.Sort(sort.Reverse(sort.IntSlice(positions)))
+			for _, p := range positions {
+				items = append(items[:p], items[p+1:]...)
+			}
+		case http.MethodPut:
+			items = moveItem(items, body.RangeStart, body.InsertBefore)
+		case http.MethodPost:
+			items = append(items[:*body.Position], append(body.URIs, items[*body.Position:]...)...)
+			w.WriteHeader(http.StatusCreated)
+		}
+		snapshot++
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d" }`, snapshot)
+	}))
+	defer server.Close()
+
+	plan := PlanPlaylistSync(items, target)
+	snapshotID, err := client.ApplyPlaylistPlan(context.Background(), "playlist_id", plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, target) {
+		t.Errorf("Expected %v, got %v", target, items)
+	}
+	if want := fmt.Sprintf("snapshot%d", snapshot); snapshotID != want {
+		t.Errorf("Expected snapshot %s, got %s", want, snapshotID)
+	}
+	// one removal, two moves and two separate additions
+	if snapshot != 5 {
+		t.Errorf("Expected 5 requests, got %d", snapshot)
+	}
+}
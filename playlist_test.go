@@ -3,16 +3,21 @@ package spotify
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestFeaturedPlaylists(t *testing.T) {
-	client, server := testClientFile(http.StatusOK, "test_data/featured_playlists.txt")
+	client, server := testClientRecorder(t, http.StatusOK, "test_data/featured_playlists.txt")
 	defer server.Close()
 
 	country := "SE"
@@ -86,6 +91,45 @@ func TestPlaylistsForUser(t *testing.T) {
 	if p.Description != expected {
 		t.Errorf("Expected '%s', got '%s'\n", expected, p.Description)
 	}
+	if p.PrimaryColor != "" {
+		t.Errorf("Expected no primary color for a null value, got '%s'\n", p.PrimaryColor)
+	}
+}
+
+func TestPlaylistPrimaryColor(t *testing.T) {
+	client, server := testClientString(http.StatusOK, `{ "id": "playlistID", "primary_color": "#FFC864" }`)
+	defer server.Close()
+
+	p, err := client.GetPlaylist(context.Background(), "playlistID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PrimaryColor != "#FFC864" {
+		t.Errorf("Expected primary color #FFC864, got '%s'\n", p.PrimaryColor)
+	}
+}
+
+func TestGetUserOwnedPlaylists(t *testing.T) {
+	var limit string
+	client, server := testClientFile(http.StatusOK, "test_data/playlists_for_user.txt", func(r *http.Request) {
+		limit = r.URL.Query().Get("limit")
+	})
+	defer server.Close()
+
+	// the fixture mixes playlists owned by several users
+	playlists, err := client.GetUserOwnedPlaylists(context.Background(), "nederlandse_top_40")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit != "50" {
+		t.Errorf("Expected limit 50, got '%s'\n", limit)
+	}
+	if l := len(playlists); l != 2 {
+		t.Fatalf("Got %d playlists, expected 2\n", l)
+	}
+	if playlists[0].Name != "Top 40" || playlists[1].Name != "Tipparade" {
+		t.Errorf("Got unexpected playlists %s and %s\n", playlists[0].Name, playlists[1].Name)
+	}
 }
 
 func TestGetPlaylist(t *testing.T) {
@@ -107,6 +151,126 @@ func TestGetPlaylist(t *testing.T) {
 	if p.SimplePlaylist.Description != "Bit of a overlap with phonk but whatever" {
 		t.Error("Description is invalid in the SimplePlaylist part of the object")
 	}
+	if u := p.WebURL(); u != "https://open.spotify.com/playlist/1h9q8vXXDl2vHOmwdsuXms" {
+		t.Error("Unexpected web URL:", u)
+	}
+	if p.PrimaryColor != "" {
+		t.Error("Expected no primary color for a null value, got", p.PrimaryColor)
+	}
+	if p.Owner.Type != "user" || p.IsEditorial() {
+		t.Errorf("Expected a user playlist, got owner type '%s'", p.Owner.Type)
+	}
+}
+
+func TestGetEditorialPlaylist(t *testing.T) {
+	client, server := testClientFile(http.StatusOK, "test_data/get_playlist_editorial.json")
+	defer server.Close()
+
+	p, err := client.GetPlaylist(context.Background(), "37i9dQZF1DXcBWIGoYBM5M")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsEditorial() {
+		t.Errorf("Expected an editorial playlist, got owner '%s'", p.Owner.ID)
+	}
+	if p.Owner.Type != "user" || p.Owner.DisplayName != "Spotify" {
+		t.Errorf("Unexpected owner %+v", p.Owner)
+	}
+}
+
+func TestGetPlaylistWithEpisodes(t *testing.T) {
+	var types string
+	client, server := testClientFile(http.StatusOK, "test_data/get_playlist_episodes_and_tracks.json", func(r *http.Request) {
+		types = r.URL.Query().Get("additional_types")
+	})
+	defer server.Close()
+
+	p, err := client.GetPlaylist(context.Background(), "23Ay9WfURne0LvtlCvYTkj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types != "episode,track" {
+		t.Errorf("Expected both episodes and tracks to be requested, got '%s'", types)
+	}
+	if len(p.Items.Items) != 4 || len(p.Tracks.Tracks) != 4 || p.Items.Total != 4 {
+		t.Fatalf("Expected 4 items, got %d", len(p.Items.Items))
+	}
+	if e := p.Items.Items[0].Track.Episode; e == nil || e.Name != "491- The Missing Middle" || e.Show.Name == "" {
+		t.Errorf("Expected the first item to be an episode, got %+v", p.Items.Items[0].Track)
+	}
+	if tr := p.Items.Items[2].Track.Track; tr == nil || tr.Name != "Typhoons" {
+		t.Errorf("Expected the third item to be a track, got %+v", p.Items.Items[2].Track)
+	}
+	if p.Tracks.Tracks[0].Track.ID != "" || p.Tracks.Tracks[2].Track.Name != "Typhoons" || p.Tracks.Tracks[0].AddedAt == "" {
+		t.Error("Expected Tracks to hold the tracks, with episodes left empty")
+	}
+
+	if _, err := client.GetPlaylist(context.Background(), "23Ay9WfURne0LvtlCvYTkj", AdditionalTypes("audiobook")); err == nil {
+		t.Error("Expected an error for an unknown additional type")
+	}
+}
+
+func TestGetPlaylistMetadata(t *testing.T) {
+	var fields string
+	client, server := testClientString(http.StatusOK, `{
+		"id": "playlist_id",
+		"name": "Road trip",
+		"snapshot_id": "snapshot",
+		"followers": { "href": null, "total": 12 },
+		"tracks": { "href": "https://api.spotify.com/v1/playlists/playlist_id/tracks", "total": 250 }
+	}`, func(r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+	})
+	defer server.Close()
+
+	p, err := client.GetPlaylistMetadata(context.Background(), "playlist_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(fields, ",tracks(href,total)") || !strings.Contains(fields, "snapshot_id") || strings.Contains(fields, "items") {
+		t.Errorf("Expected every field except the items, got '%s'", fields)
+	}
+	if p.Name != "Road trip" || p.Followers.Count != 12 || p.Tracks.Total != 250 || p.Items.Total != 250 || len(p.Tracks.Tracks) != 0 {
+		t.Errorf("Unexpected playlist %+v", p)
+	}
+}
+
+func TestPlaylistChangedSince(t *testing.T) {
+	var fields string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+		fmt.Fprint(w, `{ "snapshot_id": "snapshot2" }`)
+	}))
+	defer server.Close()
+
+	changed, snapshot, err := client.PlaylistChangedSince(context.Background(), "playlistID", "snapshot1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields != "snapshot_id" {
+		t.Errorf("Expected only the snapshot ID to be requested, got fields '%s'", fields)
+	}
+	if !changed || snapshot != "snapshot2" {
+		t.Errorf("Expected a change to snapshot2, got %t and '%s'", changed, snapshot)
+	}
+
+	changed, _, err = client.PlaylistChangedSince(context.Background(), "playlistID", "snapshot2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("Expected no change for the current snapshot")
+	}
+}
+
+func TestSimplePlaylistWebURLFallback(t *testing.T) {
+	p := SimplePlaylist{ID: "59ZbFPES4DQwEjBpWHzrtC"}
+	if u := p.WebURL(); u != "https://open.spotify.com/playlist/59ZbFPES4DQwEjBpWHzrtC" {
+		t.Error("Unexpected web URL:", u)
+	}
+	if u := (SimplePlaylist{}).WebURL(); u != "" {
+		t.Error("Expected empty web URL, got", u)
+	}
 }
 
 func TestGetPlaylistOpt(t *testing.T) {
@@ -130,6 +294,30 @@ func TestGetPlaylistOpt(t *testing.T) {
 	}
 }
 
+func TestGetPlaylistEnsureSnapshot(t *testing.T) {
+	var fields string
+	client, server := testClientString(http.StatusOK, `{ "name": "Top 40", "snapshot_id": "snapshot" }`, func(r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+	})
+	defer server.Close()
+
+	p, err := client.GetPlaylist(context.Background(), "59ZbFPES4DQwEjBpWHzrtC", EnsureSnapshot(), Fields("name,tracks.items(track(name))"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "name,tracks.items(track(name)),snapshot_id"; fields != want {
+		t.Errorf("Expected fields '%s', got '%s'\n", want, fields)
+	}
+	if p.SnapshotID != "snapshot" {
+		t.Errorf("Expected snapshot ID 'snapshot', got '%s'\n", p.SnapshotID)
+	}
+
+	_, _ = client.GetPlaylist(context.Background(), "59ZbFPES4DQwEjBpWHzrtC", Fields("snapshot_id,name"), EnsureSnapshot())
+	if want := "snapshot_id,name"; fields != want {
+		t.Errorf("Expected fields '%s', got '%s'\n", want, fields)
+	}
+}
+
 func TestFollowPlaylistSetsContentType(t *testing.T) {
 	client, server := testClientString(http.StatusOK, "", func(req *http.Request) {
 		if req.Header.Get("Content-Type") != "application/json" {
@@ -144,6 +332,33 @@ func TestFollowPlaylistSetsContentType(t *testing.T) {
 	}
 }
 
+func TestFollowEmptyResponses(t *testing.T) {
+	calls := map[string]func(*Client) error{
+		"FollowPlaylist": func(c *Client) error {
+			return c.FollowPlaylist(context.Background(), "playlistID", true)
+		},
+		"UnfollowPlaylist": func(c *Client) error {
+			return c.UnfollowPlaylist(context.Background(), "playlistID")
+		},
+		"FollowUser": func(c *Client) error {
+			return c.FollowUser(context.Background(), "userID")
+		},
+		"UnfollowArtist": func(c *Client) error {
+			return c.UnfollowArtist(context.Background(), "artistID")
+		},
+	}
+
+	for name, call := range calls {
+		for _, status := range []int{http.StatusOK, http.StatusNoContent} {
+			client, server := testClientString(status, "")
+			if err := call(client); err != nil {
+				t.Errorf("%s with an empty %d response: %v", name, status, err)
+			}
+			server.Close()
+		}
+	}
+}
+
 func TestGetPlaylistTracks(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/playlist_tracks.txt")
 	defer server.Close()
@@ -173,6 +388,62 @@ func TestGetPlaylistTracks(t *testing.T) {
 	}
 }
 
+func TestGetPlaylistTracksDelegatesToItems(t *testing.T) {
+	var types string
+	client, server := testClientFile(http.StatusOK, "test_data/playlist_items_tracks.json", func(r *http.Request) {
+		types = r.URL.Query().Get("additional_types")
+	})
+	defer server.Close()
+
+	tracks, err := client.GetPlaylistTracks(context.Background(), "playlistID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types != "track" {
+		t.Errorf("Expected additional type track, got %s\n", types)
+	}
+	if tracks.Total != 2 || len(tracks.Tracks) != 2 {
+		t.Fatalf("Got %d/%d tracks, expected 2\n", len(tracks.Tracks), tracks.Total)
+	}
+	if name := tracks.Tracks[0].Track.Name; name != "Typhoons" {
+		t.Errorf("Got '%s', expected 'Typhoons'\n", name)
+	}
+	if tracks.Tracks[0].AddedAt == "" {
+		t.Error("Expected added_at to be carried over")
+	}
+}
+
+func TestGetPlaylistTracksPage(t *testing.T) {
+	client, server := testClientFile(http.StatusOK, "test_data/playlist_items_tracks.json", func(r *http.Request) {
+		if r.URL.Path != "/playlists/playlistID/tracks" {
+			t.Errorf("Expected the tracks endpoint, got %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("offset") != "40" || query.Get("limit") != "20" {
+			t.Errorf("Expected offset 40 and limit 20, got %s", r.URL.RawQuery)
+		}
+		if query.Get("fields") != "items(track(name))" || query.Get("market") != CountryUnitedKingdom {
+			t.Errorf("Expected the fields and market options, got %s", r.URL.RawQuery)
+		}
+	})
+	defer server.Close()
+
+	tracks, err := client.GetPlaylistTracksPage(context.Background(), "playlistID", 40, 20,
+		Offset(0), Fields("items(track(name))"), Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks.Tracks) != 2 {
+		t.Errorf("Got %d tracks, expected 2", len(tracks.Tracks))
+	}
+
+	for _, window := range [][2]int{{-1, 20}, {0, 0}, {0, 101}} {
+		if _, err := client.GetPlaylistTracksPage(context.Background(), "playlistID", window[0], window[1]); err == nil {
+			t.Errorf("Expected an error for offset %d and limit %d", window[0], window[1])
+		}
+	}
+}
+
 func TestGetPlaylistItemsEpisodes(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/playlist_items_episodes.json")
 	defer server.Close()
@@ -192,6 +463,10 @@ func TestGetPlaylistItemsEpisodes(t *testing.T) {
 	if expected != actual {
 		t.Errorf("Got '%s', expected '%s'\n", actual, expected)
 	}
+	show := tracks.Items[0].Track.Episode.Show
+	if show.ID != "4XPl3uEEL9hvqMkoZrzbx5" || show.Name != "Darknet Diaries" || show.Publisher != "Jack Rhysider" {
+		t.Errorf("Unexpected show %s (%s) by %s", show.Name, show.ID, show.Publisher)
+	}
 	added := tracks.Items[0].AddedAt
 	tm, err := time.Parse(TimestampLayout, added)
 	if err != nil {
@@ -202,6 +477,436 @@ func TestGetPlaylistItemsEpisodes(t *testing.T) {
 	}
 }
 
+func TestEpisodesByShow(t *testing.T) {
+	client, server := testClientFile(http.StatusOK, "test_data/playlist_items_episodes_and_tracks.json")
+	defer server.Close()
+
+	page, err := client.GetPlaylistItems(context.Background(), "playlistID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	shows := EpisodesByShow(page.Items)
+	if len(shows) != 2 {
+		t.Fatalf("Expected 2 shows, got %d", len(shows))
+	}
+	for id, name := range map[ID]string{"2VRS1IJCTn2Nlkg33ZVfkM": "99% Invisible", "4Jgtgr4mHXNDyLldHkfEMz": "Command Line Heroes"} {
+		if items := shows[id]; len(items) != 1 || items[0].Track.Episode.Show.Name != name {
+			t.Errorf("Expected one episode of %s", name)
+		}
+	}
+}
+
+func TestGetPlaylistItemsNullAddedBy(t *testing.T) {
+	client, server := testClientFile(http.StatusOK, "test_data/playlist_items_legacy.json")
+	defer server.Close()
+
+	items, err := client.GetPlaylistItems(context.Background(), "playlistID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items.Items) != 2 {
+		t.Fatalf("Got %d items, expected 2\n", len(items.Items))
+	}
+	if id := items.Items[0].AddedByID(); id != "" {
+		t.Errorf("Expected no adder for a legacy item, got '%s'\n", id)
+	}
+	if items.Items[0].Track.Track == nil || items.Items[0].Track.Track.Name != "Api" {
+		t.Error("Expected the legacy item's track to be decoded")
+	}
+	if id := items.Items[1].AddedByID(); id != "jmperezperez" {
+		t.Errorf("Got adder '%s', expected 'jmperezperez'\n", id)
+	}
+
+	tracks := items.trackPage()
+	if id := tracks.Tracks[0].AddedByID(); id != "" {
+		t.Errorf("Expected no adder for a legacy track, got '%s'\n", id)
+	}
+}
+
+func TestItemsByContributor(t *testing.T) {
+	var items []PlaylistItem
+	err := json.Unmarshal([]byte(`[
+		{ "added_by": { "id": "alice" }, "track": { "type": "track", "id": "track1" } },
+		{ "added_by": { "id": "bob" }, "track": { "type": "track", "id": "track2" } },
+		{ "added_by": null, "track": { "type": "track", "id": "track3" } },
+		{ "added_by": { "id": "alice" }, "track": { "type": "episode", "id": "episode1" } }
+	]`), &items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := ItemsByContributor(items)
+	if len(groups) != 3 {
+		t.Fatalf("Got %d contributors, expected 3", len(groups))
+	}
+	alice := groups["alice"]
+	if len(alice) != 2 || alice[0].Track.Track.ID != "track1" || alice[1].Track.Episode.ID != "episode1" {
+		t.Errorf("Unexpected items for alice: %#v", alice)
+	}
+	if bob := groups["bob"]; len(bob) != 1 || bob[0].Track.Track.ID != "track2" {
+		t.Errorf("Unexpected items for bob: %#v", bob)
+	}
+	if unknown := groups[""]; len(unknown) != 1 || unknown[0].Track.Track.ID != "track3" {
+		t.Errorf("Unexpected items without a contributor: %#v", unknown)
+	}
+}
+
+func TestGetPlaylistWithContributors(t *testing.T) {
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/playlists/playlist_id":
+			fmt.Fprintf(w, `{ "id": "playlist_id", "collaborative": true, "tracks": {
+				"items": [
+					{ "added_by": { "id": "alice" }, "track": { "type": "track", "id": "track0" } },
+					{ "added_by": { "id": "bob" }, "track": { "type": "track", "id": "track1" } }
+				],
+				"next": "http://%s/playlists/playlist_id/tracks?offset=2&limit=2", "offset": 0, "limit": 2, "total": 3 } }`, r.Host)
+		case "/playlists/playlist_id/tracks":
+			fmt.Fprint(w, `{ "items": [ { "added_by": { "id": "alice" }, "track": { "type": "track", "id": "track2" } } ],
+				"next": null, "offset": 2, "limit": 2, "total": 3 }`)
+		case "/users/alice", "/users/bob":
+			id := strings.TrimPrefix(r.URL.Path, "/users/")
+			fmt.Fprintf(w, `{ "id": "%s", "display_name": "User %s", "images": [ { "url": "https://i.scdn.co/%s" } ] }`, id, id, id)
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	playlist, users, err := client.GetPlaylistWithContributors(context.Background(), "playlist_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(playlist.Tracks.Tracks); n != 3 || playlist.Tracks.Tracks[2].Track.ID != "track2" {
+		t.Errorf("Expected all 3 tracks, got %d", n)
+	}
+	if len(users) != 2 {
+		t.Fatalf("Expected 2 contributors, got %d", len(users))
+	}
+	for _, track := range playlist.Tracks.Tracks {
+		if u := users[ID(track.AddedByID())]; u == nil || u.DisplayName != "User "+track.AddedByID() || len(u.Images) != 1 {
+			t.Errorf("Unexpected profile for %s: %v", track.AddedByID(), u)
+		}
+	}
+}
+
+func TestGetAllPlaylistItems(t *testing.T) {
+	var requests []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		next := "null"
+		if offset+2 < 5 {
+			next = fmt.Sprintf(`"http://%s/playlists/playlistID/tracks?offset=%d&limit=2"`, r.Host, offset+2)
+		}
+		var items []string
+		for i := offset; i < offset+2 && i < 5; i++ {
+			items = append(items, fmt.Sprintf(`{ "track": { "type": "track", "id": "track%d" } }`, i))
+		}
+		fmt.Fprintf(w, `{ "items": [ %s ], "next": %s, "offset": %d, "total": 5 }`, strings.Join(items, ","), next, offset)
+	}))
+	defer server.Close()
+
+	items, err := client.GetAllPlaylistItems(context.Background(), "playlistID", Limit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 5 || len(requests) != 3 {
+		t.Fatalf("Got %d items in %d requests, expected 5 items in 3 requests", len(items), len(requests))
+	}
+	if id := items[4].Track.Track.ID; id != "track4" {
+		t.Errorf("Got '%s', expected track4", id)
+	}
+
+	requests = nil
+	items, err = client.GetAllPlaylistItems(context.Background(), "playlistID", Limit(2), Max(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || len(requests) != 2 {
+		t.Fatalf("Got %d items in %d requests, expected 3 items in 2 requests", len(items), len(requests))
+	}
+	if id := items[2].Track.Track.ID; id != "track2" {
+		t.Errorf("Got '%s', expected track2", id)
+	}
+
+	requests = nil
+	if _, err = client.GetAllPlaylistItems(context.Background(), "playlistID", Max(1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || !strings.Contains(requests[0], "limit=1") {
+		t.Errorf("Expected a single request for one item, got %v", requests)
+	}
+}
+
+func TestPlaylistTrackIterator(t *testing.T) {
+	var requests []string
+	total, failAt := 0, -1
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset == failAt {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{ "error": { "status": 500, "message": "boom" } }`)
+			return
+		}
+		var items []string
+		for i := offset; i < offset+2 && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{ "track": { "type": "track", "id": "track%d" } }`, i))
+		}
+		// Always advertise a next page, so that the iterator has to rely on
+		// the total to avoid a trailing request.
+		next := fmt.Sprintf(`"http://%s/playlists/playlistID/tracks?offset=%d&limit=2"`, r.Host, offset+2)
+		fmt.Fprintf(w, `{ "items": [ %s ], "next": %s, "offset": %d, "total": %d }`, strings.Join(items, ","), next, offset, total)
+	}))
+	defer server.Close()
+
+	collect := func(opts ...RequestOption) ([]ID, error) {
+		opts = append([]RequestOption{Limit(2)}, opts...)
+		it, err := client.GetPlaylistTracksIterator(context.Background(), "playlistID", opts...)
+		if err != nil {
+			return nil, err
+		}
+		var ids []ID
+		for {
+			track, ok, err := it.Next(context.Background())
+			if err != nil {
+				return ids, err
+			}
+			if !ok {
+				return ids, nil
+			}
+			ids = append(ids, track.Track.ID)
+		}
+	}
+
+	tests := []struct {
+		total    int
+		requests int
+	}{
+		{total: 0, requests: 1},
+		{total: 3, requests: 2},
+		{total: 4, requests: 2},
+	}
+	for _, test := range tests {
+		requests, total = nil, test.total
+		ids, err := collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != test.total || len(requests) != test.requests {
+			t.Errorf("Got %d tracks in %d requests, expected %d tracks in %d requests", len(ids), len(requests), test.total, test.requests)
+		}
+		if test.total > 0 && ids[test.total-1] != ID(fmt.Sprintf("track%d", test.total-1)) {
+			t.Errorf("Unexpected last track %s", ids[test.total-1])
+		}
+	}
+
+	for _, max := range []int{2, 3} {
+		requests, total = nil, 6
+		ids, err := collect(Max(max))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (max + 1) / 2; len(ids) != max || len(requests) != want {
+			t.Errorf("Got %d tracks in %d requests with Max(%d), expected %d tracks in %d requests", len(ids), len(requests), max, max, want)
+		}
+	}
+
+	requests, total, failAt = nil, 4, 2
+	ids, err := collect()
+	if len(ids) != 2 || len(requests) != 2 {
+		t.Errorf("Got %d tracks in %d requests before the error, expected 2 and 2", len(ids), len(requests))
+	}
+	if e, ok := err.(Error); !ok || e.Status != http.StatusInternalServerError {
+		t.Errorf("Expected a 500 error, got %v", err)
+	}
+
+	failAt = -1
+	it, err := client.GetPlaylistTracksIterator(context.Background(), "playlistID", Limit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 2; i++ {
+		if _, ok, err := it.Next(ctx); !ok || err != nil {
+			t.Fatal("Expected the tracks of the first page without a request", ok, err)
+		}
+	}
+	requests = nil
+	if _, ok, err := it.Next(ctx); ok || err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no request after cancellation, got %v", requests)
+	}
+}
+
+func TestGetPlaylistTracksInRange(t *testing.T) {
+	addedAt := []string{
+		"2023-06-30T23:59:59Z",
+		"2023-07-01T00:00:00Z",
+		"2023-08-01T00:00:00Z",
+		"",
+		"2023-07-15T12:00:00Z", // moved after the August item
+		"2023-07-31T23:59:59Z", // moved after the August item
+	}
+	var pages int
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var items []string
+		for i := offset; i < offset+2; i++ {
+			items = append(items, fmt.Sprintf(`{ "added_at": "%s", "track": { "type": "track", "id": "track%d" } }`, addedAt[i], i))
+		}
+		next := "null"
+		if offset+2 < len(addedAt) {
+			query := r.URL.Query()
+			query.Set("offset", strconv.Itoa(offset+2))
+			next = fmt.Sprintf(`"http://%s/playlists/playlist_id/tracks?%s"`, r.Host, query.Encode())
+		}
+		fmt.Fprintf(w, `{ "items": [ %s ], "next": %s, "offset": %d, "total": %d }`,
+			strings.Join(items, ","), next, offset, len(addedAt))
+	}))
+	defer server.Close()
+
+	from := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 7, 31, 23, 59, 59, 0, time.UTC)
+	ids := func(items []PlaylistItem) string {
+		var ids []string
+		for _, item := range items {
+			ids = append(ids, string(item.Track.Track.ID))
+		}
+		return strings.Join(ids, ",")
+	}
+
+	items, err := client.GetPlaylistTracksInRange(context.Background(), "playlist_id", from, to, Limit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(items); got != "track1,track4,track5" {
+		t.Errorf("Expected track1,track4,track5, got %s", got)
+	}
+	if pages != 3 {
+		t.Errorf("Expected a full scan of 3 pages, got %d", pages)
+	}
+
+	pages = 0
+	items, err = client.GetPlaylistTracksInRange(context.Background(), "playlist_id", from, to, Limit(2), AssumeAddedInOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(items); got != "track1" {
+		t.Errorf("Expected track1, got %s", got)
+	}
+	if pages != 2 {
+		t.Errorf("Expected to stop on the second page, got %d pages", pages)
+	}
+
+	if _, err := client.GetPlaylistTracksInRange(context.Background(), "playlist_id", to, from); err == nil {
+		t.Error("Expected an error for an inverted range")
+	}
+}
+
+func TestPlaylistContains(t *testing.T) {
+	items := []string{
+		`{ "track": { "uri": "spotify:track:track0" } }`,
+		`{ "track": null }`,
+		`{ "track": { "uri": "spotify:episode:episode2" } }`,
+		`{ "track": { "uri": "spotify:track:track3" } }`,
+		`{ "track": { "uri": "spotify:track:track4" } }`,
+		`{ "track": { "uri": "spotify:track:track3" } }`,
+	}
+	var offsets []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fields := r.URL.Query().Get("fields"); !strings.Contains(fields, "uri") || !strings.Contains(fields, "next") {
+			t.Errorf("Expected the fields to include the URIs and next page, got '%s'", fields)
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, strconv.Itoa(offset))
+		next := "null"
+		if offset+2 < len(items) {
+			query := r.URL.Query()
+			query.Set("offset", strconv.Itoa(offset+2))
+			next = fmt.Sprintf(`"http://%s/playlists/playlist_id/tracks?%s"`, r.Host, query.Encode())
+		}
+		fmt.Fprintf(w, `{ "items": [ %s ], "next": %s, "offset": %d, "total": %d }`,
+			strings.Join(items[offset:offset+2], ","), next, offset, len(items))
+	}))
+	defer server.Close()
+
+	found, position, err := client.PlaylistContains(context.Background(), "playlist_id", "spotify:track:track3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || position != 3 {
+		t.Errorf("Expected the track at position 3, got %t at %d", found, position)
+	}
+	if strings.Join(offsets, ",") != "0,2" {
+		t.Errorf("Expected the search to stop at the second page, got pages at offsets %v", offsets)
+	}
+
+	offsets = nil
+	found, position, err = client.PlaylistContains(context.Background(), "playlist_id", "spotify:episode:episode2")
+	if err != nil || !found || position != 2 {
+		t.Errorf("Expected the episode at position 2, got %t at %d (%v)", found, position, err)
+	}
+
+	offsets = nil
+	found, position, err = client.PlaylistContains(context.Background(), "playlist_id", "spotify:track:missing")
+	if err != nil || found || position != -1 {
+		t.Errorf("Expected the track not to be found, got %t at %d (%v)", found, position, err)
+	}
+	if len(offsets) != 3 {
+		t.Errorf("Expected all 3 pages to be searched, got %d", len(offsets))
+	}
+}
+
+func TestGetItemsForPlaylists(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/playlists/playlist1/tracks", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("offset") == "1" {
+			fmt.Fprint(w, `{ "items": [ { "track": { "type": "track", "id": "track2" } } ], "next": null, "offset": 1, "total": 2 }`)
+			return
+		}
+		fmt.Fprintf(w, `{ "items": [ { "track": { "type": "track", "id": "track1" } } ],
+			"next": "http://%s/playlists/playlist1/tracks?offset=1&limit=1", "offset": 0, "total": 2 }`, r.Host)
+	})
+	mux.HandleFunc("/playlists/playlist2/tracks", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{ "items": [ { "track": { "type": "episode", "id": "episode1" } } ], "next": null, "total": 1 }`)
+	})
+	mux.HandleFunc("/playlists/missing/tracks", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Not found", http.StatusNotFound)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+
+	res, err := client.GetItemsForPlaylists(context.Background(), []ID{"playlist1", "missing", "playlist2"}, Limit(1))
+	var be BatchError
+	if !errors.As(err, &be) {
+		t.Fatal("Expected a BatchError, got", err)
+	}
+	if len(be.Errors) != 1 || be.Errors[0].ID != "missing" || be.Errors[0].Index != 1 {
+		t.Errorf("Unexpected item errors: %v", be.Errors)
+	}
+	var se Error
+	if !errors.As(err, &se) || se.Status != http.StatusNotFound {
+		t.Error("Expected the spotify error to be reachable, got", err)
+	}
+	if _, ok := res["missing"]; ok {
+		t.Error("Expected the failed playlist to be omitted")
+	}
+	if items := res["playlist1"]; len(items) != 2 || items[1].Track.Track.ID != "track2" {
+		t.Error("Expected both pages of the first playlist, got", items)
+	}
+	if items := res["playlist2"]; len(items) != 1 || items[0].Track.Episode == nil {
+		t.Error("Expected the episode of the second playlist, got", items)
+	}
+}
+
 func TestGetPlaylistItemsTracks(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/playlist_items_tracks.json")
 	defer server.Close()
@@ -275,6 +980,44 @@ func TestGetPlaylistItemsTracksAndEpisodes(t *testing.T) {
 	}
 }
 
+func TestGetPlaylistItemsWithoutType(t *testing.T) {
+	// the response to Fields("items(track(name,album(name),show(name)))"),
+	// which leaves out the items' type
+	client, server := testClientString(http.StatusOK, `{
+		"items": [
+			{ "track": { "name": "491- The Missing Middle", "show": { "name": "99% Invisible" } } },
+			{ "track": { "name": "Typhoons", "album": { "name": "Typhoons" } } },
+			{ "track": { "name": "Episode by URI", "uri": "spotify:episode:512ojhOuo1ktJprKbVcKyQ" } },
+			{ "track": { "name": "Unknown" } }
+		]
+	}`, func(r *http.Request) {
+		if fields := r.URL.Query().Get("fields"); fields != "items(track(name,album(name),show(name)))" {
+			t.Errorf("Unexpected fields '%s'", fields)
+		}
+	})
+	defer server.Close()
+
+	items, err := client.GetPlaylistItems(context.Background(), "playlistID", Fields("items(track(name,album(name),show(name)))"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items.Items) != 4 {
+		t.Fatalf("Got %d items, expected 4", len(items.Items))
+	}
+	for i, episode := range []bool{true, false, true, false} {
+		item := items.Items[i].Track
+		if (item.Episode != nil) != episode || (item.Track != nil) == episode {
+			t.Errorf("Item %d: expected episode to be %t, got %#v", i, episode, item)
+		}
+	}
+	if name := items.Items[0].Track.Episode.Show.Name; name != "99% Invisible" {
+		t.Errorf("Got show '%s', expected '99%% Invisible'", name)
+	}
+	if name := items.Items[1].Track.Track.Album.Name; name != "Typhoons" {
+		t.Errorf("Got album '%s', expected 'Typhoons'", name)
+	}
+}
+
 func TestGetPlaylistItemsOverride(t *testing.T) {
 	var types string
 	client, server := testClientString(http.StatusForbidden, "", func(r *http.Request) {
@@ -303,6 +1046,41 @@ func TestGetPlaylistItemsDefault(t *testing.T) {
 	}
 }
 
+func TestGetPlaylistItemsClientDefault(t *testing.T) {
+	var types string
+	client, server := testClientString(http.StatusForbidden, "", func(r *http.Request) {
+		types = r.URL.Query().Get("additional_types")
+	})
+	defer server.Close()
+	WithDefaultAdditionalTypes(TrackAdditionalType)(client)
+
+	_, _ = client.GetPlaylistItems(context.Background(), "playlistID")
+	if types != "track" {
+		t.Errorf("Expected client default additional type track, got %s\n", types)
+	}
+
+	_, _ = client.GetPlaylistItems(context.Background(), "playlistID", AdditionalTypes(EpisodeAdditionalType))
+	if types != "episode" {
+		t.Errorf("Expected per-call additional type episode, got %s\n", types)
+	}
+}
+
+func TestGetPlaylistItemsUnknownAdditionalType(t *testing.T) {
+	client, server := testClientString(http.StatusOK, `{ "items": [] }`, func(r *http.Request) {
+		t.Error("Expected no request for an unknown additional type")
+	})
+	defer server.Close()
+
+	if _, err := client.GetPlaylistItems(context.Background(), "playlistID", AdditionalTypes("audiobook")); err == nil {
+		t.Error("Expected an error for a per-call additional type")
+	}
+
+	WithDefaultAdditionalTypes(TrackAdditionalType, "chapter")(client)
+	if _, err := client.GetPlaylistItems(context.Background(), "playlistID"); err == nil {
+		t.Error("Expected an error for a client default additional type")
+	}
+}
+
 func TestUserFollowsPlaylist(t *testing.T) {
 	client, server := testClientString(http.StatusOK, `[ true, false ]`)
 	defer server.Close()
@@ -360,116 +1138,530 @@ func TestCreatePlaylist(t *testing.T) {
 	client, server := testClientString(http.StatusCreated, fmt.Sprintf(newPlaylist, false))
 	defer server.Close()
 
-	p, err := client.CreatePlaylistForUser(context.Background(), "thelinmichael", "A New Playlist", "Test Description", false, false)
+	p, err := client.CreatePlaylistForUser(context.Background(), "thelinmichael", "A New Playlist", "Test Description", false, false)
+	if err != nil {
+		t.Error(err)
+	}
+	if p.IsPublic {
+		t.Error("Expected private playlist, got public")
+	}
+	if p.Name != "A New Playlist" {
+		t.Errorf("Expected 'A New Playlist', got '%s'\n", p.Name)
+	}
+	if p.Description != "Test Description" {
+		t.Errorf("Expected 'Test Description', got '%s'\n", p.Description)
+	}
+	if p.Tracks.Total != 0 {
+		t.Error("Expected new playlist to be empty")
+	}
+	if p.Collaborative {
+		t.Error("Expected non-collaborative playlist, got collaborative")
+	}
+}
+
+func TestCreateCollaborativePlaylist(t *testing.T) {
+	client, server := testClientString(http.StatusCreated, fmt.Sprintf(newPlaylist, true))
+	defer server.Close()
+
+	p, err := client.CreatePlaylistForUser(context.Background(), "thelinmichael", "A New Playlist", "Test Description", false, true)
+	if err != nil {
+		t.Error(err)
+	}
+	if p.IsPublic {
+		t.Error("Expected private playlist, got public")
+	}
+	if p.Name != "A New Playlist" {
+		t.Errorf("Expected 'A New Playlist', got '%s'\n", p.Name)
+	}
+	if p.Description != "Test Description" {
+		t.Errorf("Expected 'Test Description', got '%s'\n", p.Description)
+	}
+	if p.Tracks.Total != 0 {
+		t.Error("Expected new playlist to be empty")
+	}
+	if !p.Collaborative {
+		t.Error("Expected collaborative playlist, got non-collaborative")
+	}
+}
+
+func TestRenamePlaylist(t *testing.T) {
+	client, server := testClientString(http.StatusOK, "")
+	defer server.Close()
+
+	if err := client.ChangePlaylistName(context.Background(), ID("playlist-id"), "new name"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRenamePlaylistEmptyName(t *testing.T) {
+	var body string
+	client, server := testClientString(http.StatusOK, "", func(r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	})
+	defer server.Close()
+
+	if err := client.ChangePlaylistName(context.Background(), ID("playlist-id"), ""); err != nil {
+		t.Error(err)
+	}
+	if body != "{}" {
+		t.Errorf("Expected an empty request body, got %q", body)
+	}
+}
+
+func TestChangePlaylistAccess(t *testing.T) {
+	client, server := testClientString(http.StatusOK, "")
+	defer server.Close()
+
+	if err := client.ChangePlaylistAccess(context.Background(), ID("playlist-id"), true); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangePlaylistDescription(t *testing.T) {
+	client, server := testClientString(http.StatusOK, "")
+	defer server.Close()
+
+	if err := client.ChangePlaylistDescription(context.Background(), ID("playlist-id"), "new description"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangePlaylistNameAndAccess(t *testing.T) {
+	client, server := testClientString(http.StatusOK, "")
+	defer server.Close()
+
+	if err := client.ChangePlaylistNameAndAccess(context.Background(), ID("playlist-id"), "new_name", true); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChangePlaylistNameAccessAndDescription(t *testing.T) {
+	client, server := testClientString(http.StatusOK, "")
+	defer server.Close()
+
+	if err := client.ChangePlaylistNameAccessAndDescription(context.Background(), ID("playlist-id"), "new_name", "new description", true); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdatePlaylistDetails(t *testing.T) {
+	collaborative, public, description := true, false, ""
+	testTable := []struct {
+		Details  PlaylistDetails
+		Expected string
+	}{
+		{PlaylistDetails{Collaborative: &collaborative}, `{"collaborative":true}`},
+		{PlaylistDetails{Public: &public, Description: &description}, `{"public":false,"description":""}`},
+	}
+
+	for _, tt := range testTable {
+		var body string
+		client, server := testClientString(http.StatusOK, "", func(r *http.Request) {
+			if r.Method != http.MethodPut || r.URL.Path != "/playlists/playlist-id" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+		})
+
+		err := client.UpdatePlaylistDetails(context.Background(), "playlist-id", tt.Details)
+		server.Close()
+		if err != nil {
+			t.Error(err)
+		}
+		if body != tt.Expected {
+			t.Errorf("Expected body %s, got %s", tt.Expected, body)
+		}
+	}
+
+	client, server := testClientString(http.StatusOK, "")
+	defer server.Close()
+	if err := client.UpdatePlaylistDetails(context.Background(), "playlist-id", PlaylistDetails{}); err == nil {
+		t.Error("Expected an error without any details")
+	}
+}
+
+func TestChangePlaylistNameFailure(t *testing.T) {
+	client, server := testClientString(http.StatusForbidden, "")
+	defer server.Close()
+
+	if err := client.ChangePlaylistName(context.Background(), ID("playlist-id"), "new_name"); err == nil {
+		t.Error("Expected error but didn't get one")
+	}
+}
+
+func TestAddTracksToPlaylist(t *testing.T) {
+	client, server := testClientString(http.StatusCreated, `{ "snapshot_id" : "JbtmHBDBAYu3/bt8BOXKjzKx3i0b6LCa/wVjyl6qQ2Yf6nFXkbmzuEa+ZI/U1yF+" }`)
+	defer server.Close()
+
+	snapshot, err := client.AddTracksToPlaylist(context.Background(), ID("playlist_id"), ID("track1"), ID("track2"))
+	if err != nil {
+		t.Error(err)
+	}
+	if snapshot != "JbtmHBDBAYu3/bt8BOXKjzKx3i0b6LCa/wVjyl6qQ2Yf6nFXkbmzuEa+ZI/U1yF+" {
+		t.Error("Didn't get expected snapshot ID")
+	}
+}
+
+func TestAddTracksToPlaylistInChunks(t *testing.T) {
+	var batches [][]string
+	failAt := -1
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		batches = append(batches, body.URIs)
+		if len(batches)-1 == failAt {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, `{ "error": { "status": 502, "message": "bad gateway" } }`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d" }`, len(batches))
+	}))
+	defer server.Close()
+
+	ids := make([]ID, 250)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", i))
+	}
+
+	snapshot, err := client.AddTracksToPlaylist(context.Background(), "playlist_id", ids...)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if p.IsPublic {
-		t.Error("Expected private playlist, got public")
+	if snapshot != "snapshot3" {
+		t.Errorf("Expected the snapshot of the last batch, got '%s'", snapshot)
 	}
-	if p.Name != "A New Playlist" {
-		t.Errorf("Expected 'A New Playlist', got '%s'\n", p.Name)
+	if len(batches) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(batches))
 	}
-	if p.Description != "Test Description" {
-		t.Errorf("Expected 'Test Description', got '%s'\n", p.Description)
+	i := 0
+	for n, batch := range batches {
+		if want := []int{100, 100, 50}[n]; len(batch) != want {
+			t.Errorf("Batch %d: got %d URIs, expected %d", n, len(batch), want)
+		}
+		for _, uri := range batch {
+			if want := fmt.Sprintf("spotify:track:track%d", i); uri != want {
+				t.Fatalf("Got URI %s, expected %s", uri, want)
+			}
+			i++
+		}
 	}
-	if p.Tracks.Total != 0 {
-		t.Error("Expected new playlist to be empty")
+
+	batches, failAt = nil, 1
+	snapshot, err = client.AddTracksToPlaylist(context.Background(), "playlist_id", ids...)
+	if e, ok := err.(Error); !ok || e.Status != http.StatusBadGateway {
+		t.Errorf("Expected a 502 error, got %v", err)
 	}
-	if p.Collaborative {
-		t.Error("Expected non-collaborative playlist, got collaborative")
+	if snapshot != "snapshot1" || len(batches) != 2 {
+		t.Errorf("Expected the snapshot of the first batch after 2 requests, got '%s' after %d", snapshot, len(batches))
+	}
+
+	batches = nil
+	if snapshot, err = client.AddTracksToPlaylist(context.Background(), "playlist_id"); err != nil || snapshot != "" || len(batches) != 0 {
+		t.Errorf("Expected no request for no tracks, got %d requests", len(batches))
 	}
 }
 
-func TestCreateCollaborativePlaylist(t *testing.T) {
-	client, server := testClientString(http.StatusCreated, fmt.Sprintf(newPlaylist, true))
+func TestAddTracksToPlaylistInChunksKeepsOrder(t *testing.T) {
+	// the mock appends each batch to the playlist as Spotify would, and
+	// rate limits the first attempt at the second batch, which is retried
+	var playlist []string
+	var attempts int
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		playlist = append(playlist, body.URIs...)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d" }`, len(playlist))
+	}))
 	defer server.Close()
+	WithRetry(true)(client)
 
-	p, err := client.CreatePlaylistForUser(context.Background(), "thelinmichael", "A New Playlist", "Test Description", false, true)
-	if err != nil {
-		t.Error(err)
-	}
-	if p.IsPublic {
-		t.Error("Expected private playlist, got public")
+	// IDs in no particular order, with duplicates, so that the final order
+	// can only match if the batches are appended in sequence
+	ids := make([]ID, 250)
+	want := make([]string, len(ids))
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", (i*37)%101))
+		want[i] = "spotify:track:" + string(ids[i])
 	}
-	if p.Name != "A New Playlist" {
-		t.Errorf("Expected 'A New Playlist', got '%s'\n", p.Name)
-	}
-	if p.Description != "Test Description" {
-		t.Errorf("Expected 'Test Description', got '%s'\n", p.Description)
+
+	snapshot, err := client.AddTracksToPlaylist(context.Background(), "playlist_id", ids...)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if p.Tracks.Total != 0 {
-		t.Error("Expected new playlist to be empty")
+	if !reflect.DeepEqual(playlist, want) {
+		t.Errorf("The playlist doesn't match the order of the input:\n got %v\nwant %v", playlist, want)
 	}
-	if !p.Collaborative {
-		t.Error("Expected collaborative playlist, got non-collaborative")
+	if snapshot != "snapshot250" || attempts != 4 {
+		t.Errorf("Expected the final snapshot after 4 attempts, got '%s' after %d", snapshot, attempts)
 	}
 }
 
-func TestRenamePlaylist(t *testing.T) {
-	client, server := testClientString(http.StatusOK, "")
+func TestAddTracksToPlaylistAtPosition(t *testing.T) {
+	var positions []int
+	var reorder PlaylistReorderOptions
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			if err := json.NewDecoder(r.Body).Decode(&reorder); err != nil {
+				t.Fatal("Error decoding request body:", err)
+			}
+			fmt.Fprint(w, `{ "snapshot_id": "reordered" }`)
+			return
+		}
+		var body struct {
+			URIs     []string `json:"uris"`
+			Position *int     `json:"position"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		if body.Position == nil {
+			t.Fatal("Expected a position in the request body")
+		}
+		positions = append(positions, *body.Position)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d" }`, len(positions))
+	}))
 	defer server.Close()
 
-	if err := client.ChangePlaylistName(context.Background(), ID("playlist-id"), "new name"); err != nil {
-		t.Error(err)
+	position := 3
+	snapshot, err := client.AddTracksToPlaylistOpt(context.Background(), "playlist_id", []ID{"track1", "track2"}, &AddTracksOptions{Position: &position})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot != "snapshot1" {
+		t.Errorf("Expected snapshot 'snapshot1', got '%s'", snapshot)
+	}
+	if !reflect.DeepEqual(positions, []int{3}) {
+		t.Errorf("Expected position 3, got %v", positions)
 	}
-}
 
-func TestChangePlaylistAccess(t *testing.T) {
-	client, server := testClientString(http.StatusOK, "")
-	defer server.Close()
+	snapshot, err = client.ReorderPlaylistTracks(context.Background(), "playlist_id", PlaylistReorderOptions{
+		RangeStart:   3,
+		RangeLength:  2,
+		InsertBefore: 0,
+		SnapshotID:   snapshot,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reorder.SnapshotID != "snapshot1" || snapshot != "reordered" {
+		t.Errorf("Expected the reorder to chain snapshot 'snapshot1', got '%s'", reorder.SnapshotID)
+	}
 
-	if err := client.ChangePlaylistAccess(context.Background(), ID("playlist-id"), true); err != nil {
-		t.Error(err)
+	positions = nil
+	ids := make([]ID, 250)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", i))
+	}
+	if _, err := client.AddTracksToPlaylistOpt(context.Background(), "playlist_id", ids, &AddTracksOptions{Position: &position}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(positions, []int{3, 103, 203}) {
+		t.Errorf("Expected batches at positions 3, 103 and 203, got %v", positions)
+	}
+	if position != 3 {
+		t.Errorf("The option's position was modified to %d", position)
+	}
+
+	position = -1
+	if _, err := client.AddTracksToPlaylistOpt(context.Background(), "playlist_id", ids, &AddTracksOptions{Position: &position}); err == nil {
+		t.Error("Expected an error for a negative position")
 	}
 }
 
-func TestChangePlaylistDescription(t *testing.T) {
-	client, server := testClientString(http.StatusOK, "")
+func TestAddTracksToPlaylistAppendsByDefault(t *testing.T) {
+	var bodies []map[string]interface{}
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{ "snapshot_id": "snapshot" }`)
+	}))
 	defer server.Close()
 
-	if err := client.ChangePlaylistDescription(context.Background(), ID("playlist-id"), "new description"); err != nil {
-		t.Error(err)
+	ids := make([]ID, 150)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("track%d", i))
+	}
+	if _, err := client.AddTracksToPlaylist(context.Background(), "playlist_id", ids...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.AddTracksToPlaylistOpt(context.Background(), "playlist_id", ids[:1], &AddTracksOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if _, ok := body["position"]; ok {
+			t.Errorf("Request %d: expected no position, got %v", i, body["position"])
+		}
 	}
 }
 
-func TestChangePlaylistNameAndAccess(t *testing.T) {
-	client, server := testClientString(http.StatusOK, "")
+func TestAddTracksToPlaylistNormalizesURIs(t *testing.T) {
+	var uris []string
+	client, server := testClientString(http.StatusCreated, `{ "snapshot_id" : "snapshot" }`, func(r *http.Request) {
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		uris = body.URIs
+	})
 	defer server.Close()
 
-	if err := client.ChangePlaylistNameAndAccess(context.Background(), ID("playlist-id"), "new_name", true); err != nil {
-		t.Error(err)
+	_, err := client.AddTracksToPlaylist(context.Background(), "playlist_id", "4iV5W9uYEdYUVa79Axb7Rh", "spotify:track:1301WleyT98MSxVHPZCA6M")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uris) != 2 || uris[0] != "spotify:track:4iV5W9uYEdYUVa79Axb7Rh" || uris[1] != "spotify:track:1301WleyT98MSxVHPZCA6M" {
+		t.Error("Unexpected URIs:", uris)
 	}
-}
 
-func TestChangePlaylistNameAccessAndDescription(t *testing.T) {
-	client, server := testClientString(http.StatusOK, "")
-	defer server.Close()
+	for _, id := range []ID{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "spotify:track:spotify:track:1301WleyT98MSxVHPZCA6M", ""} {
+		if _, err := client.AddTracksToPlaylist(context.Background(), "playlist_id", id); err == nil {
+			t.Errorf("Expected an error for %q", id)
+		}
+		if _, err := client.RemoveTracksFromPlaylist(context.Background(), "playlist_id", id); err == nil {
+			t.Errorf("Expected an error removing %q", id)
+		}
+		if err := client.ReplacePlaylistTracks(context.Background(), "playlist_id", id); err == nil {
+			t.Errorf("Expected an error replacing with %q", id)
+		}
+		tracks := []TrackToRemove{NewTrackToRemove(string(id), []int{0})}
+		if _, err := client.RemoveTracksFromPlaylistOpt(context.Background(), "playlist_id", tracks, ""); err == nil {
+			t.Errorf("Expected an error removing %q at a position", id)
+		}
+	}
 
-	if err := client.ChangePlaylistNameAccessAndDescription(context.Background(), ID("playlist-id"), "new_name", "new description", true); err != nil {
-		t.Error(err)
+	if uri := NewTrackToRemove("spotify:track:1301WleyT98MSxVHPZCA6M", nil).URI; uri != "spotify:track:1301WleyT98MSxVHPZCA6M" {
+		t.Error("Unexpected URI to remove:", uri)
 	}
 }
 
-func TestChangePlaylistNameFailure(t *testing.T) {
-	client, server := testClientString(http.StatusForbidden, "")
+func TestAddAlbumToPlaylist(t *testing.T) {
+	// the second page holds enough tracks that they have to be added in two batches
+	var secondPage []string
+	for i := 1; i <= 100; i++ {
+		secondPage = append(secondPage, fmt.Sprintf(`{ "id": "d3t%d", "disc_number": 3, "track_number": %d }`, i, i))
+	}
+
+	var added [][]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/albums/album_id/tracks", func(w http.ResponseWriter, r *http.Request) {
+		if market := r.URL.Query().Get("market"); market != CountryUnitedKingdom {
+			t.Errorf("Expected market %s, got '%s'", CountryUnitedKingdom, market)
+		}
+		if r.URL.Query().Get("offset") == "" {
+			fmt.Fprintf(w, `{ "items": [
+				{ "id": "d2t1", "disc_number": 2, "track_number": 1, "is_playable": true },
+				{ "id": "d1t2", "disc_number": 1, "track_number": 2, "is_playable": true },
+				{ "id": "d1t3", "disc_number": 1, "track_number": 3, "is_playable": false },
+				{ "id": "d1t1", "disc_number": 1, "track_number": 1 }
+			], "next": "http://%s/albums/album_id/tracks?market=GB&offset=4", "total": 104 }`, r.Host)
+			return
+		}
+		fmt.Fprintf(w, `{ "items": [ %s ], "next": null, "total": 104 }`, strings.Join(secondPage, ","))
+	})
+	mux.HandleFunc("/playlists/playlist_id/tracks", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		added = append(added, body.URIs)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d" }`, len(added))
+	})
+	client, server := testClientHandler(mux)
 	defer server.Close()
 
-	if err := client.ChangePlaylistName(context.Background(), ID("playlist-id"), "new_name"); err == nil {
-		t.Error("Expected error but didn't get one")
+	snapshot, err := client.AddAlbumToPlaylist(context.Background(), "playlist_id", "album_id", Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot != "snapshot2" {
+		t.Errorf("Expected the snapshot ID of the last batch, got '%s'", snapshot)
+	}
+	if len(added) != 2 || len(added[0]) != 100 || len(added[1]) != 3 {
+		t.Fatalf("Expected batches of 100 and 3 tracks, got %d batches", len(added))
+	}
+	want := []string{"spotify:track:d1t1", "spotify:track:d1t2", "spotify:track:d2t1", "spotify:track:d3t1"}
+	for i, uri := range want {
+		if added[0][i] != uri {
+			t.Errorf("Expected track %d to be %s, got %s", i, uri, added[0][i])
+		}
+	}
+	if last := added[1][2]; last != "spotify:track:d3t100" {
+		t.Errorf("Expected last track to be spotify:track:d3t100, got %s", last)
 	}
 }
 
-func TestAddTracksToPlaylist(t *testing.T) {
-	client, server := testClientString(http.StatusCreated, `{ "snapshot_id" : "JbtmHBDBAYu3/bt8BOXKjzKx3i0b6LCa/wVjyl6qQ2Yf6nFXkbmzuEa+ZI/U1yF+" }`)
+func TestAddArtistTopTracksToPlaylist(t *testing.T) {
+	var added []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/artists/artist_id/top-tracks", func(w http.ResponseWriter, r *http.Request) {
+		if market := r.URL.Query().Get("market"); market != CountryUnitedKingdom {
+			t.Errorf("Expected market %s, got '%s'", CountryUnitedKingdom, market)
+		}
+		fmt.Fprint(w, `{ "tracks": [ { "id": "track1" }, { "id": "track2" } ] }`)
+	})
+	mux.HandleFunc("/playlists/playlist_id/tracks", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		added = body.URIs
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{ "snapshot_id": "snapshot" }`)
+	})
+	client, server := testClientHandler(mux)
 	defer server.Close()
 
-	snapshot, err := client.AddTracksToPlaylist(context.Background(), ID("playlist_id"), ID("track1"), ID("track2"))
+	snapshot, err := client.AddArtistTopTracksToPlaylist(context.Background(), "playlist_id", "artist_id", CountryUnitedKingdom)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	if snapshot != "JbtmHBDBAYu3/bt8BOXKjzKx3i0b6LCa/wVjyl6qQ2Yf6nFXkbmzuEa+ZI/U1yF+" {
-		t.Error("Didn't get expected snapshot ID")
+	if snapshot != "snapshot" {
+		t.Errorf("Unexpected snapshot ID '%s'", snapshot)
+	}
+	if len(added) != 2 || added[0] != "spotify:track:track1" || added[1] != "spotify:track:track2" {
+		t.Error("Unexpected tracks added:", added)
+	}
+
+	if _, err := client.AddArtistTopTracksToPlaylist(context.Background(), "playlist_id", "artist_id", ""); err == nil {
+		t.Error("Expected an error when no country is specified")
 	}
 }
 
@@ -513,6 +1705,46 @@ func TestRemoveTracksFromPlaylist(t *testing.T) {
 	}
 }
 
+func TestRemoveTracksFromPlaylistRemovesAllOccurrences(t *testing.T) {
+	playlist := []string{
+		"spotify:track:track1",
+		"spotify:track:track2",
+		"spotify:track:track1",
+		"spotify:track:track3",
+	}
+	client, server := testClientString(http.StatusOK, `{ "snapshot_id" : "snapshot" }`, func(req *http.Request) {
+		var body struct {
+			Tracks []map[string]interface{} `json:"tracks"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if l := len(body.Tracks); l != 1 {
+			t.Fatalf("Expected duplicate IDs to be collapsed into 1 track, got %d\n", l)
+		}
+		if _, ok := body.Tracks[0]["positions"]; ok {
+			t.Error("Track object shouldn't contain 'positions' field")
+		}
+		// emulate Spotify: a track without positions removes every occurrence
+		remaining := playlist[:0]
+		for _, uri := range playlist {
+			if uri != body.Tracks[0]["uri"] {
+				remaining = append(remaining, uri)
+			}
+		}
+		playlist = remaining
+	})
+	defer server.Close()
+
+	_, err := client.RemoveTracksFromPlaylist(context.Background(), "playlistID", "track1", "track1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(playlist) != 2 || playlist[0] != "spotify:track:track2" || playlist[1] != "spotify:track:track3" {
+		t.Errorf("Expected both occurrences of track1 to be removed, got %v\n", playlist)
+	}
+}
+
 func TestRemoveTracksFromPlaylistOpt(t *testing.T) {
 	client, server := testClientString(http.StatusOK, `{ "snapshot_id" : "JbtmHBDBAYu3/bt8BOXKjzKx3i0b6LCa/wVjyl6qQ2Yf6nFXkbmzuEa+ZI/U1yF+" }`, func(req *http.Request) {
 		requestBody, err := io.ReadAll(req.Body)
@@ -648,6 +1880,78 @@ func TestClient_ReplacePlaylistItems(t *testing.T) {
 	}
 }
 
+func TestReplacePlaylistItemsInChunks(t *testing.T) {
+	type request struct {
+		method string
+		uris   []URI
+	}
+	var requests []request
+	failAt := -1
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			URIs []URI `json:"uris"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		requests = append(requests, request{r.Method, body.URIs})
+		if len(requests)-1 == failAt {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{ "error": { "status": 500, "message": "boom" } }`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{ "snapshot_id": "snapshot%d" }`, len(requests))
+	}))
+	defer server.Close()
+
+	items := make([]URI, 250)
+	for i := range items {
+		items[i] = URI(fmt.Sprintf("spotify:episode:episode%d", i))
+	}
+
+	snapshot, err := client.ReplacePlaylistItems(context.Background(), "playlistID", items...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot != "snapshot3" {
+		t.Errorf("Expected the snapshot of the last request, got '%s'", snapshot)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requests))
+	}
+	for n, want := range []request{{"PUT", items[:100]}, {"POST", items[100:200]}, {"POST", items[200:]}} {
+		if got := requests[n]; got.method != want.method || !reflect.DeepEqual(got.uris, want.uris) {
+			t.Errorf("Request %d: got %s with %d items, expected %s with %d items", n, got.method, len(got.uris), want.method, len(want.uris))
+		}
+	}
+
+	// the second chunk of added items fails
+	requests, failAt = nil, 2
+	snapshot, err = client.ReplacePlaylistItems(context.Background(), "playlistID", items...)
+	var partial PartialReplaceError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialReplaceError, got %v", err)
+	}
+	if partial.Written != 200 || partial.SnapshotID != "snapshot2" || snapshot != "snapshot2" {
+		t.Errorf("Expected 200 items written in snapshot2, got %d in '%s' ('%s')", partial.Written, partial.SnapshotID, snapshot)
+	}
+	var spotifyErr Error
+	if !errors.As(err, &spotifyErr) || spotifyErr.Status != http.StatusInternalServerError {
+		t.Errorf("Expected the underlying error to be reported, got %v", err)
+	}
+
+	// nothing is written if replacing the first chunk fails
+	requests, failAt = nil, 0
+	if _, err = client.ReplacePlaylistItems(context.Background(), "playlistID", items...); err == nil || errors.As(err, &partial) {
+		t.Errorf("Expected a plain error, got %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("Expected no more requests after the failed replacement, got %d", len(requests))
+	}
+}
+
 func TestReplacePlaylistTracks(t *testing.T) {
 	client, server := testClientString(http.StatusCreated, "")
 	defer server.Close()
@@ -718,6 +2022,102 @@ func TestReorderPlaylistRequest(t *testing.T) {
 	}
 }
 
+func TestReorderPlaylistTracksSnapshotMismatch(t *testing.T) {
+	current := "snapshot0"
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body PlaylistReorderOptions
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal("Error decoding request body:", err)
+		}
+		switch {
+		case body.RangeStart == 99:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{ "error": { "status": 400, "message": "Index out of bounds" } }`)
+		case body.SnapshotID != "" && body.SnapshotID != current:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{ "error": { "status": 400, "message": "Invalid snapshot id" } }`)
+		default:
+			current = fmt.Sprintf("snapshot%d", len(current))
+			fmt.Fprintf(w, `{ "snapshot_id": "%s" }`, current)
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	// chaining the returned snapshots succeeds
+	snapshot := "snapshot0"
+	for i := 0; i < 2; i++ {
+		var err error
+		snapshot, err = client.ReorderPlaylistTracks(ctx, "playlist", PlaylistReorderOptions{InsertBefore: 2, SnapshotID: snapshot})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if snapshot != current {
+		t.Errorf("Expected snapshot %s, got %s", current, snapshot)
+	}
+
+	// a stale snapshot is a mismatch
+	_, err := client.ReorderPlaylistTracks(ctx, "playlist", PlaylistReorderOptions{InsertBefore: 2, SnapshotID: "snapshot0"})
+	var mismatch SnapshotMismatchError
+	if !errors.As(err, &mismatch) || mismatch.SnapshotID != "snapshot0" {
+		t.Fatalf("Expected a snapshot mismatch, got %v", err)
+	}
+	var spotifyErr Error
+	if !errors.As(err, &spotifyErr) || spotifyErr.Status != http.StatusBadRequest {
+		t.Errorf("Expected the mismatch to wrap a 400 error, got %v", err)
+	}
+
+	// other errors aren't
+	_, err = client.ReorderPlaylistTracks(ctx, "playlist", PlaylistReorderOptions{RangeStart: 99, SnapshotID: current})
+	if _, ok := err.(Error); !ok {
+		t.Errorf("Expected a plain error for a bad range, got %v", err)
+	}
+}
+
+func TestPutPlaylistTracks(t *testing.T) {
+	var bodies []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{ "snapshot_id": "snapshot" }`)
+	}))
+	defer server.Close()
+
+	_, err := client.PutPlaylistTracks(context.Background(), "playlistID", PutTracksBody{
+		URIs:    []URI{"spotify:track:track1"},
+		Reorder: &PlaylistReorderOptions{RangeStart: 3, InsertBefore: 8},
+	})
+	if err != ErrConflictingBody {
+		t.Error("Expected ErrConflictingBody, got", err)
+	}
+	if _, err := client.PutPlaylistTracks(context.Background(), "playlistID", PutTracksBody{}); err == nil {
+		t.Error("Expected an error for an empty body")
+	}
+	if len(bodies) != 0 {
+		t.Fatal("Expected invalid bodies to be rejected without a request")
+	}
+
+	snapshot, err := client.PutPlaylistTracks(context.Background(), "playlistID", PutTracksBody{
+		Reorder: &PlaylistReorderOptions{RangeStart: 3, InsertBefore: 8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot != "snapshot" {
+		t.Error("Unexpected snapshot ID:", snapshot)
+	}
+	if _, err := client.PutPlaylistTracks(context.Background(), "playlistID", PutTracksBody{URIs: []URI{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{`{"range_start":3,"insert_before":8}`, `{"uris":[]}`}
+	if len(bodies) != 2 || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("Expected bodies %v, got %v", want, bodies)
+	}
+}
+
 func TestSetPlaylistImage(t *testing.T) {
 	client, server := testClientString(http.StatusAccepted, "", func(req *http.Request) {
 		if ct := req.Header.Get("Content-Type"); ct != "image/jpeg" {
@@ -731,14 +2131,84 @@ func TestSetPlaylistImage(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if !bytes.Equal(body, []byte("Zm9v")) {
-			t.Errorf("invalid request body: want Zm9v, got %s", string(body))
+		if !bytes.Equal(body, []byte("/9j/4AAQZm9v")) {
+			t.Errorf("invalid request body: want /9j/4AAQZm9v, got %s", string(body))
+		}
+	})
+	defer server.Close()
+
+	err := client.SetPlaylistImage(context.Background(), "playlist", bytes.NewReader([]byte("\xFF\xD8\xFF\xE0\x00\x10foo")))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetPlaylistImageValidation(t *testing.T) {
+	var requests []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	jpeg := func(size int) []byte {
+		b := make([]byte, size)
+		copy(b, "\xFF\xD8\xFF\xE0")
+		return b
+	}
+	largest := base64.StdEncoding.DecodedLen(MaxPlaylistImageSize)
+	if err := client.SetPlaylistImage(ctx, "playlist", bytes.NewReader(jpeg(largest))); err != nil {
+		t.Errorf("Expected the largest image to be accepted, got %v", err)
+	}
+	if err := client.SetPlaylistImage(ctx, "playlist", bytes.NewReader(jpeg(largest+1))); err != ErrImageTooLarge {
+		t.Errorf("Expected ErrImageTooLarge, got %v", err)
+	}
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	if err := client.SetPlaylistImage(ctx, "playlist", bytes.NewReader(png)); err != ErrImageNotJPEG {
+		t.Errorf("Expected ErrImageNotJPEG, got %v", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(jpeg(10))
+	if err := client.SetPlaylistImageBase64(ctx, "playlist", encoded); err != nil {
+		t.Error(err)
+	}
+	if err := client.SetPlaylistImageBase64(ctx, "playlist", base64.StdEncoding.EncodeToString(png)); err != ErrImageNotJPEG {
+		t.Errorf("Expected ErrImageNotJPEG, got %v", err)
+	}
+	if err := client.SetPlaylistImageBase64(ctx, "playlist", "not base64!"); err == nil {
+		t.Error("Expected an error for invalid base64")
+	}
+	if err := client.SetPlaylistImageBase64(ctx, "playlist", base64.StdEncoding.EncodeToString(jpeg(largest+1))); err != ErrImageTooLarge {
+		t.Errorf("Expected ErrImageTooLarge, got %v", err)
+	}
+
+	if len(requests) != 2 || len(requests[0]) != MaxPlaylistImageSize || requests[1] != encoded {
+		t.Errorf("Expected only the 2 valid images to be sent, got %d requests", len(requests))
+	}
+}
+
+func TestGetPlaylistImages(t *testing.T) {
+	client, server := testClientString(http.StatusOK, `[
+		{ "height": 640, "url": "https://i.scdn.co/image/ab67616d0000b273", "width": 640 },
+		{ "height": null, "url": "https://mosaic.scdn.co/60/ab67616d00001e02", "width": null }
+	]`, func(r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/playlists/playlist_id/images" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
 		}
 	})
 	defer server.Close()
 
-	err := client.SetPlaylistImage(context.Background(), "playlist", bytes.NewReader([]byte("foo")))
+	images, err := client.GetPlaylistImages(context.Background(), "playlist_id")
 	if err != nil {
 		t.Fatal(err)
 	}
+	want := []Image{
+		{Height: 640, Width: 640, URL: "https://i.scdn.co/image/ab67616d0000b273"},
+		{URL: "https://mosaic.scdn.co/60/ab67616d00001e02"},
+	}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("Expected %+v, got %+v", want, images)
+	}
 }
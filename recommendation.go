@@ -2,6 +2,7 @@ package spotify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -41,6 +42,18 @@ type RecommendationSeed struct {
 // MaxNumberOfSeeds allowed by Spotify for a recommendation request.
 const MaxNumberOfSeeds = 5
 
+// validate checks that s holds between 1 and [MaxNumberOfSeeds] seeds, counting
+// artists, tracks and genres together.
+func (s Seeds) validate() error {
+	switch n := s.count(); {
+	case n == 0:
+		return errors.New("spotify: at least one artist, track or genre seed is required")
+	case n > MaxNumberOfSeeds:
+		return fmt.Errorf("spotify: got %d seeds, but at most %d artist, track and genre seeds are allowed in total", n, MaxNumberOfSeeds)
+	}
+	return nil
+}
+
 // setSeedValues sets url values into v for each seed in seeds
 func setSeedValues(seeds Seeds, v url.Values) {
 	if len(seeds.Artists) != 0 {
@@ -75,26 +88,34 @@ func setTrackAttributesValues(trackAttributes *TrackAttributes, values url.Value
 // very new or obscure there might not be enough data to generate a list of
 // tracks.
 //
-// Supported options: [Limit], [Country].
+// Between 1 and [MaxNumberOfSeeds] seeds must be given, counting artists,
+// tracks and genres together; otherwise an error is returned without making
+// a request.  The seeds of the result report how many tracks each seed
+// contributed to the pool that the recommendations were picked from.
+//
+// Supported options: [Limit], [Market].  For backwards compatibility, a
+// [Country] option is sent as the market.
 //
 // [list of recommended tracks]: https://developer.spotify.com/documentation/web-api/reference/get-recommendations
 func (c *Client) GetRecommendations(ctx context.Context, seeds Seeds, trackAttributes *TrackAttributes, opts ...RequestOption) (*Recommendations, error) {
-	v := processOptions(opts...).urlParams
-
-	if seeds.count() == 0 {
-		return nil, fmt.Errorf("spotify: at least one seed is required")
+	if err := seeds.validate(); err != nil {
+		return nil, err
 	}
-	if seeds.count() > MaxNumberOfSeeds {
-		return nil, fmt.Errorf("spotify: exceeded maximum of %d seeds", MaxNumberOfSeeds)
+
+	opts, err := c.marketOptions(ctx, withCountryAsMarket(opts))
+	if err != nil {
+		return nil, err
 	}
 
+	v := processOptions(opts...).urlParams
+
 	setSeedValues(seeds, v)
 	setTrackAttributesValues(trackAttributes, v)
 
 	spotifyURL := c.baseURL + "recommendations?" + v.Encode()
 
 	var recommendations Recommendations
-	err := c.get(ctx, spotifyURL, &recommendations)
+	err = c.get(ctx, spotifyURL, &recommendations)
 	if err != nil {
 		return nil, err
 	}
@@ -118,3 +139,35 @@ func (c *Client) GetAvailableGenreSeeds(ctx context.Context) ([]string, error) {
 
 	return genreSeeds["genres"], nil
 }
+
+// GetArtistGenreSeeds returns the genres of the specified artist that are
+// also valid genre seeds for [GetRecommendations].  It combines [GetArtistGenres]
+// with [GetAvailableGenreSeeds].  Artist genres are matched against the seeds
+// case-insensitively, with spaces treated as hyphens (so the artist genre
+// "Hip Hop" matches the seed "hip-hop").  The seed spelling is returned.
+func (c *Client) GetArtistGenreSeeds(ctx context.Context, artistID ID) ([]string, error) {
+	genres, err := c.GetArtistGenres(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+	seeds, err := c.GetAvailableGenreSeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]bool, len(seeds))
+	for _, seed := range seeds {
+		available[seed] = true
+	}
+
+	result := []string{}
+	for _, genre := range genres {
+		seed := strings.ReplaceAll(strings.ToLower(genre), " ", "-")
+		if available[seed] {
+			result = append(result, seed)
+			delete(available, seed)
+		}
+	}
+
+	return result, nil
+}
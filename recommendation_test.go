@@ -2,7 +2,10 @@ package spotify
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -28,6 +31,34 @@ func TestGetRecommendations(t *testing.T) {
 	if recommendations.Tracks[0].Artists[0].Name != "Heinrich Isaac" {
 		t.Error("Expected the artist of the first recommended track to be Heinrich Isaac")
 	}
+	if len(recommendations.Seeds) != 4 {
+		t.Fatalf("Expected 4 seeds, got %d", len(recommendations.Seeds))
+	}
+	seed := recommendations.Seeds[1]
+	if seed.ID != "0c6xIDDpzE81m2q797ordA" || seed.Type != "TRACK" || seed.InitialPoolSize != 250 || seed.AfterRelinkingSize != 207 {
+		t.Errorf("Unexpected seed %#v", seed)
+	}
+}
+
+func TestGetRecommendationsSeedCount(t *testing.T) {
+	client, server := testClientString(http.StatusOK, "", func(*http.Request) {
+		t.Error("No request should be made for an invalid number of seeds")
+	})
+	defer server.Close()
+	WithMarketFromUser()(client)
+
+	if _, err := client.GetRecommendations(context.Background(), Seeds{}, nil); err == nil {
+		t.Error("Expected an error without seeds")
+	}
+
+	seeds := Seeds{
+		Artists: []ID{"4NHQUGzhtTLFvgF5SZesLK", "5PHQUGzhtTUIvgF5SZesGY"},
+		Tracks:  []ID{"0c6xIDDpzE81m2q797ordA", "1301WleyT98MSxVHPZCA6M"},
+		Genres:  []string{"classical", "country"},
+	}
+	if _, err := client.GetRecommendations(context.Background(), seeds, nil); err == nil {
+		t.Error("Expected an error for 6 seeds")
+	}
 }
 
 func TestSetSeedValues(t *testing.T) {
@@ -70,3 +101,31 @@ func TestSetEmptyTrackAttributesValues(t *testing.T) {
 		t.Errorf("Expected track attributes values to be empty but got %s", actualValues)
 	}
 }
+
+func TestGetArtistGenreSeeds(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/artists/0TnOYISbd1XYRBk9myaseg", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{ "id": "0TnOYISbd1XYRBk9myaseg", "genres": [ "Hip Hop", "pop", "dance pop", "pop" ] }`)
+	})
+	mux.HandleFunc("/recommendations/available-genre-seeds", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{ "genres": [ "classical", "hip-hop", "pop" ] }`)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+
+	genres, err := client.GetArtistGenres(context.Background(), "0TnOYISbd1XYRBk9myaseg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genres) != 4 {
+		t.Errorf("Expected 4 artist genres, got %v", genres)
+	}
+
+	seeds, err := client.GetArtistGenreSeeds(context.Background(), "0TnOYISbd1XYRBk9myaseg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"hip-hop", "pop"}; !reflect.DeepEqual(seeds, want) {
+		t.Errorf("Expected %v, got %v", want, seeds)
+	}
+}
@@ -1,6 +1,8 @@
 package spotify
 
 import (
+	"context"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -10,6 +12,20 @@ type RequestOption func(*requestOptions)
 
 type requestOptions struct {
 	urlParams url.Values
+
+	// extraParams holds the parameters added with [Param], which are only
+	// merged into urlParams once all of the typed options have been applied.
+	extraParams url.Values
+
+	ensureSnapshot bool
+
+	// maxItems caps the number of items collected by helpers that fetch
+	// several pages.  Zero means no cap.
+	maxItems int
+
+	// addedInOrder lets [Client.GetPlaylistTracksInRange] stop paging once
+	// it is past the range.
+	addedInOrder bool
 }
 
 // Limit sets the number of entries that a request should return.
@@ -19,7 +35,11 @@ func Limit(amount int) RequestOption {
 	}
 }
 
-// Market enables track re-linking.
+// Market enables track re-linking, and limits the results to content that is
+// playable in the market.  The code must be an ISO 3166-1 alpha-2 country
+// code, such as [CountryUnitedKingdom], or [MarketFromToken] to use the
+// country of the user's account.  Any other code makes the call return an
+// error without making a request.
 func Market(code string) RequestOption {
 	return func(o *requestOptions) {
 		o.urlParams.Set("market", code)
@@ -90,12 +110,69 @@ func After(after string) RequestOption {
 // Fields can be excluded by prefixing them with an exclamation mark, for example;
 //
 //	fields = "tracks.items(track(name,href,album(!name,href)))"
+//
+// Episodes in a playlist appear under the "track" key too, so episode fields
+// are selected with "items(track(...))" as well, for example
+// "items(track(name,show(name)))".  A [FieldsBuilder] can compose the filter.
 func Fields(fields string) RequestOption {
 	return func(o *requestOptions) {
 		o.urlParams.Set("fields", fields)
 	}
 }
 
+// EnsureSnapshot makes sure that "snapshot_id" is part of the response, even
+// when a narrow [Fields] filter would otherwise exclude it.  It is useful for
+// optimistic-concurrency flows that pass the snapshot ID back to Spotify in
+// subsequent edits.  The option may be specified before or after [Fields];
+// without a [Fields] filter all fields are returned anyway, so it has no effect.
+func EnsureSnapshot() RequestOption {
+	return func(o *requestOptions) {
+		o.ensureSnapshot = true
+	}
+}
+
+// Max caps the total number of items collected by helpers that fetch several
+// pages, such as [GetAllPlaylistItems].  Once n items have been
+// collected no further pages are requested, and the final page is truncated
+// if it holds more items than needed.  It is not sent to Spotify, and calls
+// that return a single page ignore it; use [Limit] to set the page size.
+func Max(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.maxItems = n
+	}
+}
+
+// AssumeAddedInOrder tells [Client.GetPlaylistTracksInRange] that the items
+// of the playlist are in the order in which they were added, so that it can
+// stop paging at the first item added after the range.  Playlists that are
+// only ever appended to are in that order, but reordering or inserting items
+// breaks it, in which case items in the range can be missed.  It is not sent
+// to Spotify.
+func AssumeAddedInOrder() RequestOption {
+	return func(o *requestOptions) {
+		o.addedInOrder = true
+	}
+}
+
+// Param adds an arbitrary query parameter to a request.  It is an escape
+// hatch for parameters that Spotify supports but that this package does not
+// provide a dedicated option for yet, such as new or undocumented ones.
+// Specifying the same key more than once adds multiple values.
+//
+// Warning: Param does not validate the key or value, and typed options take
+// precedence over it.  If a typed option (for example [Limit] or [Market]) or
+// the call itself sets the same key, the values given to Param are silently
+// discarded, regardless of the order in which the options are specified.
+func Param(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.extraParams == nil {
+			o.extraParams = url.Values{}
+		}
+		o.extraParams.Add(key, value)
+	}
+}
+
+// Range is a time period used by [Timerange].
 type Range string
 
 const (
@@ -116,13 +193,51 @@ func Timerange(timerange Range) RequestOption {
 	}
 }
 
+// AlbumGroups filters the albums returned by [Client.GetArtistAlbums] to the
+// given album groups, such as [AlbumTypeAlbum] and [AlbumTypeSingle], which
+// can be used to leave out the albums an artist merely appears on.  The types
+// may also be bitwise OR'd together.  Without any types, it has no effect.
+func AlbumGroups(types ...AlbumType) RequestOption {
+	var groups AlbumType
+	for _, t := range types {
+		groups |= t
+	}
+
+	return func(o *requestOptions) {
+		if groups != 0 {
+			o.urlParams.Set("include_groups", groups.encode())
+		}
+	}
+}
+
+// AdditionalType is a type of playlist item, given to [AdditionalTypes].
 type AdditionalType string
 
+// The types of playlist items.
 const (
-	EpisodeAdditionalType = "episode"
-	TrackAdditionalType   = "track"
+	EpisodeAdditionalType AdditionalType = "episode"
+	TrackAdditionalType   AdditionalType = "track"
 )
 
+// defaultAdditionalTypes are the item types that [Client.GetPlaylistItems]
+// requests unless the client is configured with [WithDefaultAdditionalTypes].
+var defaultAdditionalTypes = []AdditionalType{EpisodeAdditionalType, TrackAdditionalType}
+
+// validateAdditionalTypes returns an error if the comma-separated list of
+// types holds a type other than [EpisodeAdditionalType] and
+// [TrackAdditionalType].
+func validateAdditionalTypes(csv string) error {
+	if csv == "" {
+		return nil
+	}
+	for _, t := range strings.Split(csv, ",") {
+		if t := AdditionalType(t); t != EpisodeAdditionalType && t != TrackAdditionalType {
+			return fmt.Errorf("spotify: unknown additional type %q", t)
+		}
+	}
+	return nil
+}
+
 // AdditionalTypes is a list of item types that your client supports besides
 // the default track type. Valid types are: [EpisodeAdditionalType] and
 // [TrackAdditionalType].
@@ -147,5 +262,146 @@ func processOptions(options ...RequestOption) requestOptions {
 		opt(&o)
 	}
 
+	for key, values := range o.extraParams {
+		if _, ok := o.urlParams[key]; !ok {
+			o.urlParams[key] = values
+		}
+	}
+
+	if fields := o.urlParams.Get("fields"); o.ensureSnapshot && fields != "" && !hasTopLevelField(fields, "snapshot_id") {
+		o.urlParams.Set("fields", fields+",snapshot_id")
+	}
+
 	return o
 }
+
+// marketOptions prepends the client's default market, if one is configured
+// with [WithDefaultMarket] or [WithMarketFromUser], to opts.  If opts already
+// set a market, they are returned as they are, without looking up the
+// user's country.
+func (c *Client) marketOptions(ctx context.Context, opts []RequestOption) ([]RequestOption, error) {
+	if processOptions(opts...).urlParams.Get("market") != "" {
+		return opts, nil
+	}
+
+	market := c.defaultMarket
+	if market == "" && c.marketFromUser {
+		var err error
+		if market, err = c.userCountry(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if market == "" {
+		return opts, nil
+	}
+
+	return append([]RequestOption{Market(market)}, opts...), nil
+}
+
+// userCountry returns the current user's country for [WithMarketFromUser],
+// reading it with [Client.CurrentUser] the first time.  The result is cached
+// even if it is empty, as it is when the token lacks [ScopeUserReadPrivate].
+// The lock isn't held during the request, so concurrent first calls may each
+// read the country.
+func (c *Client) userCountry(ctx context.Context) (string, error) {
+	c.userMarketMu.Lock()
+	fetched, country := c.userMarketFetched, c.userMarket
+	c.userMarketMu.Unlock()
+	if fetched {
+		return country, nil
+	}
+
+	user, err := c.CurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.userMarketMu.Lock()
+	c.userMarket, c.userMarketFetched = user.Country, true
+	c.userMarketMu.Unlock()
+	return user.Country, nil
+}
+
+// validateMarket returns an error if code is neither a two-letter country
+// code nor [MarketFromToken].
+func validateMarket(code string) error {
+	if code == MarketFromToken {
+		return nil
+	}
+	if len(code) == 2 && isLetter(code[0]) && isLetter(code[1]) {
+		return nil
+	}
+	return fmt.Errorf("spotify: invalid market %q, expected an ISO 3166-1 alpha-2 country code or %q", code, MarketFromToken)
+}
+
+func isLetter(b byte) bool {
+	return 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z'
+}
+
+// checkMarketParam validates the market parameter of a request URL, if it
+// has one, so that an invalid [Market] is reported by every endpoint that
+// supports it, rather than as an obscure error from Spotify.
+func checkMarketParam(u *url.URL) error {
+	if markets, ok := u.Query()["market"]; ok {
+		for _, market := range markets {
+			if err := validateMarket(market); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// countryAsMarket moves a country parameter to the market parameter, unless
+// a market is already set.
+func (o *requestOptions) countryAsMarket() {
+	if country := o.urlParams.Get("country"); country != "" {
+		if o.urlParams.Get("market") == "" {
+			o.urlParams.Set("market", country)
+		}
+		o.urlParams.Del("country")
+	}
+}
+
+// withCountryAsMarket is for endpoints that filter content by "market"
+// rather than "country".  Spotify silently ignores a country parameter on
+// those endpoints, so a [Country] option is sent as the market instead,
+// unless a [Market] option was given as well.  It returns opts with the
+// method expression (*requestOptions).countryAsMarket appended as a
+// [RequestOption], without modifying the caller's slice.  Being last, it
+// sees every other option; call it before [Client.marketOptions] so that a
+// [Country] takes precedence over the client's default market:
+//
+//	opts, err := c.marketOptions(ctx, withCountryAsMarket(opts))
+func withCountryAsMarket(opts []RequestOption) []RequestOption {
+	return append(opts[:len(opts):len(opts)], (*requestOptions).countryAsMarket)
+}
+
+// hasTopLevelField reports whether name is selected at the top level of the
+// fields filter, ignoring anything nested inside parentheses.
+func hasTopLevelField(fields, name string) bool {
+	depth, start := 0, 0
+	for i := 0; i <= len(fields); i++ {
+		if i < len(fields) {
+			switch fields[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth != 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if fields[start:i] == name {
+			return true
+		}
+		start = i + 1
+	}
+	return false
+}
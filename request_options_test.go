@@ -1,6 +1,9 @@
 package spotify
 
 import (
+	"context"
+	"net/http"
+	"reflect"
 	"testing"
 )
 
@@ -24,3 +27,178 @@ func TestOptions(t *testing.T) {
 		t.Errorf("Expected '%v', got '%v'", expected, actual)
 	}
 }
+
+func TestParamOption(t *testing.T) {
+	t.Parallel()
+
+	resultSet := processOptions(
+		Param("limit", "50"),
+		Limit(13),
+		Param("include_external", "audio"),
+		Param("include_external", "video"),
+	)
+
+	expected := "include_external=audio&include_external=video&limit=13"
+	actual := resultSet.urlParams.Encode()
+	if actual != expected {
+		t.Errorf("Expected '%v', got '%v'", expected, actual)
+	}
+}
+
+// TestCountryAndMarketParams checks that each endpoint sends the region as the
+// query parameter that Spotify expects for it, since Spotify silently ignores
+// the wrong one.
+func TestCountryAndMarketParams(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	seeds := Seeds{Artists: []ID{"artist"}}
+	tests := []struct {
+		name  string
+		param string
+		call  func(c *Client) error
+	}{
+		{"FeaturedPlaylists", "country", func(c *Client) error {
+			_, _, err := c.FeaturedPlaylists(ctx, Country(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetCategories", "country", func(c *Client) error {
+			_, err := c.GetCategories(ctx, Country(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetCategory", "country", func(c *Client) error {
+			_, err := c.GetCategory(ctx, "party", Country(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetCategoryPlaylists", "country", func(c *Client) error {
+			_, err := c.GetCategoryPlaylists(ctx, "party", Country(CountryUnitedKingdom))
+			return err
+		}},
+		{"NewReleases", "country", func(c *Client) error {
+			_, err := c.NewReleases(ctx, Country(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetArtistsTopTracks", "market", func(c *Client) error {
+			_, err := c.GetArtistsTopTracks(ctx, "artist", CountryUnitedKingdom)
+			return err
+		}},
+		{"CurrentUsersTracks", "market", func(c *Client) error {
+			_, err := c.CurrentUsersTracks(ctx, Country(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetRecommendations", "market", func(c *Client) error {
+			_, err := c.GetRecommendations(ctx, seeds, nil, Country(CountryUnitedKingdom))
+			return err
+		}},
+		{"Search", "market", func(c *Client) error {
+			_, err := c.Search(ctx, "query", SearchTypeTrack, Market(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetAlbum", "market", func(c *Client) error {
+			_, err := c.GetAlbum(ctx, "album", Market(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetTrack", "market", func(c *Client) error {
+			_, err := c.GetTrack(ctx, "track", Market(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetShow", "market", func(c *Client) error {
+			_, err := c.GetShow(ctx, "show", Market(CountryUnitedKingdom))
+			return err
+		}},
+		{"GetPlaylistItems", "market", func(c *Client) error {
+			_, err := c.GetPlaylistItems(ctx, "playlist", Market(CountryUnitedKingdom))
+			return err
+		}},
+		{"CurrentUsersAlbums", "market", func(c *Client) error {
+			_, err := c.CurrentUsersAlbums(ctx, Market(CountryUnitedKingdom))
+			return err
+		}},
+		{"PlayerCurrentlyPlaying", "market", func(c *Client) error {
+			_, err := c.PlayerCurrentlyPlaying(ctx, Market(CountryUnitedKingdom))
+			return err
+		}},
+	}
+
+	for _, test := range tests {
+		var country, market string
+		client, server := testClientString(http.StatusOK, `{ "albums": {} }`, func(r *http.Request) {
+			country, market = r.URL.Query().Get("country"), r.URL.Query().Get("market")
+		})
+
+		if err := test.call(client); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		server.Close()
+
+		got, other := market, country
+		if test.param == "country" {
+			got, other = country, market
+		}
+		if got != CountryUnitedKingdom || other != "" {
+			t.Errorf("%s: expected %s=%s, got country='%s' market='%s'", test.name, test.param, CountryUnitedKingdom, country, market)
+		}
+	}
+}
+
+func TestMarketValidation(t *testing.T) {
+	var requests int
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	for _, market := range []string{CountryUnitedKingdom, "se", MarketFromToken} {
+		if _, err := client.GetAlbum(ctx, "album", Market(market)); err != nil {
+			t.Errorf("Unexpected error for market %q: %v", market, err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+
+	requests = 0
+	for _, market := range []string{"", "GBR", "G1", "from token"} {
+		if _, err := client.GetTrack(ctx, "track", Market(market)); err == nil {
+			t.Errorf("Expected an error for market %q", market)
+		}
+	}
+	WithDefaultMarket("United Kingdom")(client)
+	if _, err := client.Search(ctx, "query", SearchTypeTrack); err == nil {
+		t.Error("Expected an error for an invalid default market")
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests for invalid markets, got %d", requests)
+	}
+}
+
+func TestCountryAsMarketPrecedence(t *testing.T) {
+	var markets []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("country") != "" {
+			t.Errorf("Expected no country parameter, got %s", r.URL.RawQuery)
+		}
+		markets = append(markets, r.URL.Query().Get("market"))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	WithDefaultMarket(CountryGermany)(client)
+	ctx := context.Background()
+
+	for _, opts := range [][]RequestOption{
+		nil,
+		{Country(CountryUnitedKingdom)},
+		{Country(CountryUnitedKingdom), Market(CountryUSA)},
+		{Market(CountryUSA), Country(CountryUnitedKingdom)},
+	} {
+		if _, err := client.CurrentUsersTracks(ctx, opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{CountryGermany, CountryUnitedKingdom, CountryUSA, CountryUSA}
+	if !reflect.DeepEqual(markets, want) {
+		t.Errorf("Expected markets %v, got %v", want, markets)
+	}
+}
@@ -2,11 +2,12 @@ package spotify
 
 import (
 	"context"
+	"errors"
 	"strings"
 )
 
 const (
-	// MarketFromToken can be used in place of the Options.Country parameter
+	// MarketFromToken can be passed to [Market] in place of a country code
 	// if the Client has a valid access token.  In this case, the
 	// results will be limited to content that is playable in the
 	// country associated with the user's account.  The user must have
@@ -22,12 +23,12 @@ type SearchType int
 // that can be bitwise OR'd together to search for multiple types of content
 // simultaneously.
 const (
-	SearchTypeAlbum    SearchType = 1 << iota
-	SearchTypeArtist              = 1 << iota
-	SearchTypePlaylist            = 1 << iota
-	SearchTypeTrack               = 1 << iota
-	SearchTypeShow                = 1 << iota
-	SearchTypeEpisode             = 1 << iota
+	SearchTypeAlbum SearchType = 1 << iota
+	SearchTypeArtist
+	SearchTypePlaylist
+	SearchTypeTrack
+	SearchTypeShow
+	SearchTypeEpisode
 )
 
 func (st SearchType) encode() string {
@@ -55,6 +56,10 @@ func (st SearchType) encode() string {
 
 // SearchResult contains the results of a call to [Search].
 // Fields that weren't searched for will be nil pointers.
+//
+// Each category is paged independently with the Next*Results and
+// Previous*Results methods, which replace only that category's page and
+// return [ErrNoMorePages] once it is exhausted.
 type SearchResult struct {
 	Artists   *FullArtistPage     `json:"artists"`
 	Albums    *SimpleAlbumPage    `json:"albums"`
@@ -112,26 +117,41 @@ type SearchResult struct {
 // Other possible field filters, depending on object types being searched,
 // include "genre", "upc", and "isrc".  For example "damian genre:reggae-pop".
 //
-// If the Market field is specified in the options, then the results will only
+// If the [Market] option is specified, then the results will only
 // contain artists, albums, and tracks playable in the specified country
 // (playlist results are not affected by the Market option).  Additionally,
-// the constant MarketFromToken can be used with authenticated clients.
+// the constant [MarketFromToken] can be used with authenticated clients.
 // If the client has a valid access token, then the results will only include
-// content playable in the user's country.
+// content playable in the user's country.  When a market is given, track
+// results are subject to [Track Relinking]: [FullTrack.LinkedFrom] identifies
+// the originally requested track and [FullTrack.IsPlayable] reports whether
+// the track can be played in that market.
 //
-// Supported options: [Limit], [Market], [Offset].
+// Supported options: [Limit], [Market], [Offset].  The [Limit] and [Offset]
+// apply to each of the searched types separately.
 //
+// [Track Relinking]: https://developer.spotify.com/documentation/general/guides/track-relinking-guide/
 // [Spotify catalog information]: https://developer.spotify.com/documentation/web-api/reference/search
 func (c *Client) Search(ctx context.Context, query string, t SearchType, opts ...RequestOption) (*SearchResult, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	types := t.encode()
+	if types == "" {
+		return nil, errors.New("spotify: no search type given")
+	}
+
 	v := processOptions(opts...).urlParams
 	v.Set("q", query)
-	v.Set("type", t.encode())
+	v.Set("type", types)
 
 	spotifyURL := c.baseURL + "search?" + v.Encode()
 
 	var result SearchResult
 
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -139,12 +159,51 @@ func (c *Client) Search(ctx context.Context, query string, t SearchType, opts ..
 	return &result, err
 }
 
+// loadSearchPage fetches a page of search results and stores the categories
+// present in the response into s.  Each category pages independently, so the
+// other categories in s are left untouched.  The affected pages are replaced
+// rather than decoded in place, which ensures that a null next or previous
+// link in the response clears the old one.
+func (c *Client) loadSearchPage(ctx context.Context, url string, s *SearchResult) error {
+	url, err := c.pageURL(url)
+	if err != nil {
+		return err
+	}
+
+	var page SearchResult
+
+	err = c.get(ctx, url, &page)
+	if err != nil {
+		return err
+	}
+
+	if page.Artists != nil {
+		s.Artists = page.Artists
+	}
+	if page.Albums != nil {
+		s.Albums = page.Albums
+	}
+	if page.Playlists != nil {
+		s.Playlists = page.Playlists
+	}
+	if page.Tracks != nil {
+		s.Tracks = page.Tracks
+	}
+	if page.Shows != nil {
+		s.Shows = page.Shows
+	}
+	if page.Episodes != nil {
+		s.Episodes = page.Episodes
+	}
+	return nil
+}
+
 // NextArtistResults loads the next page of artists into the specified search result.
 func (c *Client) NextArtistResults(ctx context.Context, s *SearchResult) error {
 	if s.Artists == nil || s.Artists.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Artists.Next, s)
+	return c.loadSearchPage(ctx, s.Artists.Next, s)
 }
 
 // PreviousArtistResults loads the previous page of artists into the specified search result.
@@ -152,7 +211,7 @@ func (c *Client) PreviousArtistResults(ctx context.Context, s *SearchResult) err
 	if s.Artists == nil || s.Artists.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Artists.Previous, s)
+	return c.loadSearchPage(ctx, s.Artists.Previous, s)
 }
 
 // NextAlbumResults loads the next page of albums into the specified search result.
@@ -160,7 +219,7 @@ func (c *Client) NextAlbumResults(ctx context.Context, s *SearchResult) error {
 	if s.Albums == nil || s.Albums.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Albums.Next, s)
+	return c.loadSearchPage(ctx, s.Albums.Next, s)
 }
 
 // PreviousAlbumResults loads the previous page of albums into the specified search result.
@@ -168,7 +227,7 @@ func (c *Client) PreviousAlbumResults(ctx context.Context, s *SearchResult) erro
 	if s.Albums == nil || s.Albums.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Albums.Previous, s)
+	return c.loadSearchPage(ctx, s.Albums.Previous, s)
 }
 
 // NextPlaylistResults loads the next page of playlists into the specified search result.
@@ -176,7 +235,7 @@ func (c *Client) NextPlaylistResults(ctx context.Context, s *SearchResult) error
 	if s.Playlists == nil || s.Playlists.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Playlists.Next, s)
+	return c.loadSearchPage(ctx, s.Playlists.Next, s)
 }
 
 // PreviousPlaylistResults loads the previous page of playlists into the specified search result.
@@ -184,7 +243,7 @@ func (c *Client) PreviousPlaylistResults(ctx context.Context, s *SearchResult) e
 	if s.Playlists == nil || s.Playlists.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Playlists.Previous, s)
+	return c.loadSearchPage(ctx, s.Playlists.Previous, s)
 }
 
 // PreviousTrackResults loads the previous page of tracks into the specified search result.
@@ -192,7 +251,7 @@ func (c *Client) PreviousTrackResults(ctx context.Context, s *SearchResult) erro
 	if s.Tracks == nil || s.Tracks.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Tracks.Previous, s)
+	return c.loadSearchPage(ctx, s.Tracks.Previous, s)
 }
 
 // NextTrackResults loads the next page of tracks into the specified search result.
@@ -200,7 +259,7 @@ func (c *Client) NextTrackResults(ctx context.Context, s *SearchResult) error {
 	if s.Tracks == nil || s.Tracks.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Tracks.Next, s)
+	return c.loadSearchPage(ctx, s.Tracks.Next, s)
 }
 
 // PreviousShowResults loads the previous page of shows into the specified search result.
@@ -208,7 +267,7 @@ func (c *Client) PreviousShowResults(ctx context.Context, s *SearchResult) error
 	if s.Shows == nil || s.Shows.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Shows.Previous, s)
+	return c.loadSearchPage(ctx, s.Shows.Previous, s)
 }
 
 // NextShowResults loads the next page of shows into the specified search result.
@@ -216,7 +275,7 @@ func (c *Client) NextShowResults(ctx context.Context, s *SearchResult) error {
 	if s.Shows == nil || s.Shows.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Shows.Next, s)
+	return c.loadSearchPage(ctx, s.Shows.Next, s)
 }
 
 // PreviousEpisodeResults loads the previous page of episodes into the specified search result.
@@ -224,7 +283,7 @@ func (c *Client) PreviousEpisodeResults(ctx context.Context, s *SearchResult) er
 	if s.Episodes == nil || s.Episodes.Previous == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Episodes.Previous, s)
+	return c.loadSearchPage(ctx, s.Episodes.Previous, s)
 }
 
 // NextEpisodeResults loads the next page of episodes into the specified search result.
@@ -232,5 +291,5 @@ func (c *Client) NextEpisodeResults(ctx context.Context, s *SearchResult) error
 	if s.Episodes == nil || s.Episodes.Next == "" {
 		return ErrNoMorePages
 	}
-	return c.get(ctx, s.Episodes.Next, s)
+	return c.loadSearchPage(ctx, s.Episodes.Next, s)
 }
@@ -3,6 +3,8 @@ package spotify
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -56,6 +58,41 @@ func TestSearchTracks(t *testing.T) {
 	}
 }
 
+func TestSearchTracksWithMarket(t *testing.T) {
+	var market string
+	client, server := testClientFile(http.StatusOK, "test_data/search_tracks_market.txt", func(r *http.Request) {
+		market = r.URL.Query().Get("market")
+	})
+	defer server.Close()
+
+	result, err := client.Search(context.Background(), "uptown", SearchTypeTrack, Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if market != CountryUnitedKingdom {
+		t.Errorf("Expected market %s, got '%s'", CountryUnitedKingdom, market)
+	}
+	if result.Tracks == nil || len(result.Tracks.Tracks) != 2 {
+		t.Fatal("Didn't receive track results")
+	}
+
+	relinked := result.Tracks.Tracks[0]
+	if relinked.LinkedFrom == nil || relinked.LinkedFrom.ID != "6VwLFHkxcSz3F3J0kDZ3in" {
+		t.Error("Expected relinked track to include linked_from")
+	}
+	if !relinked.Playable(CountryUnitedKingdom) {
+		t.Error("Expected relinked track to be playable")
+	}
+
+	unplayable := result.Tracks.Tracks[1]
+	if unplayable.LinkedFrom != nil {
+		t.Error("Expected no linked_from for a track that wasn't relinked")
+	}
+	if unplayable.IsPlayable == nil || *unplayable.IsPlayable {
+		t.Error("Expected is_playable to be false")
+	}
+}
+
 func TestSearchPlaylistTrack(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/search_trackplaylist.txt")
 	defer server.Close()
@@ -78,6 +115,91 @@ func TestSearchPlaylistTrack(t *testing.T) {
 	}
 }
 
+func TestSearchCombinedTypes(t *testing.T) {
+	var query url.Values
+	client, server := testClientString(http.StatusOK, `{
+		"artists": { "items": [ { "id": "artist1" } ], "total": 1 },
+		"albums": { "items": [ { "id": "album1" } ], "total": 1 },
+		"shows": { "items": [], "total": 0 }
+	}`, func(r *http.Request) {
+		query = r.URL.Query()
+	})
+	defer server.Close()
+
+	result, err := client.Search(context.Background(), "roadhouse blues", SearchTypeArtist|SearchTypeAlbum|SearchTypeShow,
+		Limit(5), Offset(10), Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"q":      {"roadhouse blues"},
+		"type":   {"album,artist,show"},
+		"limit":  {"5"},
+		"offset": {"10"},
+		"market": {CountryUnitedKingdom},
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("Expected query %v, got %v", want, query)
+	}
+	if result.Artists == nil || result.Albums == nil || result.Shows == nil {
+		t.Fatal("Expected artist, album and show results")
+	}
+	if result.Tracks != nil || result.Playlists != nil || result.Episodes != nil {
+		t.Error("Expected no results for types that weren't searched for")
+	}
+
+	query = nil
+	if _, err := client.Search(context.Background(), "roadhouse blues", 0); err == nil {
+		t.Error("Expected an error without a search type")
+	}
+	if query != nil {
+		t.Error("Expected no request without a search type")
+	}
+}
+
+func TestNextSearchResultsIndependent(t *testing.T) {
+	var path string
+	client, server := testClientString(http.StatusOK, `{
+		"tracks": {
+			"href": "https://api.spotify.com/v1/search?query=holiday&type=track&offset=20&limit=20",
+			"items": [ { "id": "track2", "name": "Holiday (Part 2)" } ],
+			"limit": 20,
+			"next": null,
+			"offset": 20,
+			"previous": "https://api.spotify.com/v1/search?query=holiday&type=track&offset=0&limit=20",
+			"total": 21
+		}
+	}`, func(r *http.Request) {
+		path = r.URL.RequestURI()
+	})
+	defer server.Close()
+
+	playlists := &SimplePlaylistPage{basePage: basePage{Next: server.URL + "/search?type=playlist&offset=20"}}
+	result := &SearchResult{
+		Tracks: &FullTrackPage{
+			basePage: basePage{Next: server.URL + "/search?type=track&offset=20", Total: 21},
+			Tracks:   []FullTrack{{SimpleTrack: SimpleTrack{ID: "track1"}}},
+		},
+		Playlists: playlists,
+	}
+
+	if err := client.NextTrackResults(context.Background(), result); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/search?type=track&offset=20" {
+		t.Error("Requested wrong URL:", path)
+	}
+	if len(result.Tracks.Tracks) != 1 || result.Tracks.Tracks[0].ID != "track2" {
+		t.Error("Expected the track page to be replaced, got", result.Tracks.Tracks)
+	}
+	if result.Playlists != playlists {
+		t.Error("Expected the playlist page to be left untouched")
+	}
+	if err := client.NextTrackResults(context.Background(), result); err != ErrNoMorePages {
+		t.Error("Expected ErrNoMorePages after the last track page, got", err)
+	}
+}
+
 func TestPrevNextSearchPageErrors(t *testing.T) {
 	client, server := testClientString(0, "")
 	defer server.Close()
@@ -2,7 +2,8 @@ package spotify
 
 import (
 	"context"
-	"net/http"
+	"fmt"
+	"html"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +17,16 @@ type SavedShow struct {
 	FullShow `json:"show"`
 }
 
+// SavedEpisode contains an episode saved in the current user's library, along
+// with the time it was saved.
+type SavedEpisode struct {
+	// The date and time the episode was saved, represented as an ISO 8601 UTC
+	// timestamp with a zero offset (YYYY-MM-DDTHH:MM:SSZ). You can use
+	// [TimestampLayout] to convert this to a [time.Time].
+	AddedAt     string `json:"added_at"`
+	EpisodePage `json:"episode"`
+}
+
 // FullShow contains full data about a show.
 type FullShow struct {
 	SimpleShow
@@ -35,9 +46,14 @@ type SimpleShow struct {
 	// The copyright statements of the show.
 	Copyrights []Copyright `json:"copyrights"`
 
-	// A description of the show.
+	// A description of the show, with any HTML tags removed.
 	Description string `json:"description"`
 
+	// A description of the show, which may contain HTML tags.  See
+	// [SimpleShow.PlainDescription] for a plain-text version that is always
+	// available.
+	HTMLDescription string `json:"html_description"`
+
 	// Whether or not the show has explicit content
 	// (true = yes it does; false = no it does not OR unknown).
 	Explicit bool `json:"explicit"`
@@ -75,6 +91,9 @@ type SimpleShow struct {
 	// The publisher of the show.
 	Publisher string `json:"publisher"`
 
+	// The total number of episodes in the show.
+	TotalEpisodes Numeric `json:"total_episodes"`
+
 	// The object type: “show”.
 	Type string `json:"type"`
 
@@ -86,9 +105,14 @@ type EpisodePage struct {
 	// A URL to a 30 second preview (MP3 format) of the episode.
 	AudioPreviewURL string `json:"audio_preview_url"`
 
-	// A description of the episode.
+	// A description of the episode, with any HTML tags removed.
 	Description string `json:"description"`
 
+	// A description of the episode, which may contain HTML tags.  See
+	// [EpisodePage.PlainDescription] for a plain-text version that is always
+	// available.
+	HTMLDescription string `json:"html_description"`
+
 	// The episode length in milliseconds.
 	Duration_ms Numeric `json:"duration_ms"`
 
@@ -134,6 +158,10 @@ type EpisodePage struct {
 	// "year", "month", or "day".
 	ReleaseDatePrecision string `json:"release_date_precision"`
 
+	// Part of the response when a content restriction is applied, or nil.
+	// See [EpisodeRestriction].
+	Restrictions *EpisodeRestriction `json:"restrictions"`
+
 	// The user’s most recent position in the episode. Set if the
 	// supplied access token is a user token and has the scope
 	// user-read-playback-position.
@@ -149,6 +177,116 @@ type EpisodePage struct {
 	URI URI `json:"uri"`
 }
 
+// RestrictionReason is the reason that Spotify restricts an item.
+type RestrictionReason string
+
+// The reasons that Spotify gives for restricting an item.  Spotify may add
+// reasons in future, so expect values other than these.
+const (
+	// RestrictionMarket means the item isn't available in the given market.
+	RestrictionMarket RestrictionReason = "market"
+	// RestrictionProduct means the item isn't available for the user's
+	// subscription type.
+	RestrictionProduct RestrictionReason = "product"
+	// RestrictionExplicit means the item is explicit and the user's account
+	// is set to not play explicit content.
+	RestrictionExplicit RestrictionReason = "explicit"
+	// RestrictionPaymentRequired means the item can only be played after
+	// paying for it, for example a paid podcast episode.
+	RestrictionPaymentRequired RestrictionReason = "payment_required"
+)
+
+// EpisodeRestriction describes why an episode can't be played, so that
+// an app can explain it to the user rather than playback silently failing.
+type EpisodeRestriction struct {
+	// The reason for the restriction.
+	Reason RestrictionReason `json:"reason"`
+}
+
+// IsMarketRestricted reports whether the episode is restricted because it
+// isn't available in the given market.
+func (r *EpisodeRestriction) IsMarketRestricted() bool {
+	return r != nil && r.Reason == RestrictionMarket
+}
+
+// IsProductRestricted reports whether the episode is restricted because it
+// isn't available for the user's subscription type.
+func (r *EpisodeRestriction) IsProductRestricted() bool {
+	return r != nil && r.Reason == RestrictionProduct
+}
+
+// IsExplicitRestricted reports whether the episode is restricted because it
+// is explicit and the user's account doesn't allow explicit content.
+func (r *EpisodeRestriction) IsExplicitRestricted() bool {
+	return r != nil && r.Reason == RestrictionExplicit
+}
+
+// IsPaymentRequired reports whether the episode is restricted because it
+// must be paid for.
+func (r *EpisodeRestriction) IsPaymentRequired() bool {
+	return r != nil && r.Reason == RestrictionPaymentRequired
+}
+
+// PlainDescription returns a plain-text description of the show, suitable
+// for previews.  It is the Description field, or if that is empty (for
+// example because a [Fields] filter excluded it), HTMLDescription with the
+// HTML tags removed.
+func (s *SimpleShow) PlainDescription() string {
+	if s.Description != "" {
+		return s.Description
+	}
+	return stripHTML(s.HTMLDescription)
+}
+
+// PlainDescription returns a plain-text description of the episode, suitable
+// for previews.  It is the Description field, or if that is empty,
+// HTMLDescription with the HTML tags removed.
+func (e *EpisodePage) PlainDescription() string {
+	if e.Description != "" {
+		return e.Description
+	}
+	return stripHTML(e.HTMLDescription)
+}
+
+// stripHTML converts the simple HTML that Spotify uses for descriptions to
+// plain text.  Tags are removed, line breaks, paragraphs and list items start
+// a new line, entities are unescaped, and runs of whitespace are collapsed.
+func stripHTML(s string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			b.WriteString(s)
+			break
+		}
+		end := strings.IndexByte(s[start:], '>')
+		if end < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:start])
+
+		tag := strings.ToLower(strings.Trim(s[start+1:start+end], "/ "))
+		if i := strings.IndexAny(tag, " \t\n"); i >= 0 {
+			tag = tag[:i]
+		}
+		switch tag {
+		case "br", "p", "li", "ul", "ol", "div":
+			b.WriteByte('\n')
+		}
+		s = s[start+end+1:]
+	}
+
+	lines := strings.Split(html.UnescapeString(b.String()), "\n")
+	text := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			text = append(text, line)
+		}
+	}
+	return strings.Join(text, "\n")
+}
+
 type ResumePointObject struct {
 	// 	Whether or not the episode has been fully played by the user.
 	FullyPlayed bool `json:"fully_played"`
@@ -182,6 +320,11 @@ func (e *EpisodePage) ReleaseDateTime() time.Time {
 //
 // [specific show]: https://developer.spotify.com/documentation/web-api/reference/get-a-show
 func (c *Client) GetShow(ctx context.Context, id ID, opts ...RequestOption) (*FullShow, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "shows/" + string(id)
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
 		spotifyURL += "?" + params
@@ -189,7 +332,7 @@ func (c *Client) GetShow(ctx context.Context, id ID, opts ...RequestOption) (*Fu
 
 	var result FullShow
 
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -197,12 +340,62 @@ func (c *Client) GetShow(ctx context.Context, id ID, opts ...RequestOption) (*Fu
 	return &result, nil
 }
 
+// maxShowsPerRequest is the number of show IDs Spotify accepts in a single
+// request for several shows.
+const maxShowsPerRequest = 50
+
+// GetShows retrieves information about [several shows] given their
+// [Spotify ID]s.  The shows are returned in the order of the IDs, without
+// their episodes; use [Client.GetShow] or [Client.GetShowEpisodes] for
+// those.  If a show is not found, or not available in the market, a nil
+// value is returned in the appropriate position.  More than 50 IDs may be
+// passed; they are fetched in batches of 50.
+//
+// Supported options: [Market].
+//
+// [several shows]: https://developer.spotify.com/documentation/web-api/reference/get-multiple-shows
+// [Spotify ID]: https://developer.spotify.com/documentation/web-api/#spotify-uris-and-ids
+func (c *Client) GetShows(ctx context.Context, ids []ID, opts ...RequestOption) ([]*SimpleShow, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	shows := make([]*SimpleShow, 0, len(ids))
+	err = c.fetchChunks(ids, maxShowsPerRequest, "shows", func(chunk []ID) (int, error) {
+		params := processOptions(opts...).urlParams
+		params.Set("ids", strings.Join(toStringSlice(chunk), ","))
+
+		spotifyURL := fmt.Sprintf("%sshows?%s", c.baseURL, params.Encode())
+
+		var s struct {
+			Shows []*SimpleShow `json:"shows"`
+		}
+
+		if err := c.get(ctx, spotifyURL, &s); err != nil {
+			return 0, err
+		}
+		shows = append(shows, s.Shows...)
+		return len(s.Shows), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shows, nil
+}
+
 // GetShowEpisodes retrieves paginated [episode information] about a specific show.
 //
 // Supported options: [Market], [Limit], [Offset].
 //
 // [episode information]: https://developer.spotify.com/documentation/web-api/reference/get-a-shows-episodes
 func (c *Client) GetShowEpisodes(ctx context.Context, id string, opts ...RequestOption) (*SimpleEpisodePage, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "shows/" + id + "/episodes"
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
 		spotifyURL += "?" + params
@@ -210,7 +403,7 @@ func (c *Client) GetShowEpisodes(ctx context.Context, id string, opts ...Request
 
 	var result SimpleEpisodePage
 
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -219,22 +412,51 @@ func (c *Client) GetShowEpisodes(ctx context.Context, id string, opts ...Request
 }
 
 // SaveShowsForCurrentUser [saves one or more shows] to current Spotify user's library.
+// This call requires the [ScopeUserLibraryModify] scope.  More than 50 IDs may
+// be passed; they are saved in batches of 50.
 //
 // [saves one or more shows]: https://developer.spotify.com/documentation/web-api/reference/save-shows-user
 func (c *Client) SaveShowsForCurrentUser(ctx context.Context, ids []ID) error {
-	spotifyURL := c.baseURL + "me/shows?ids=" + strings.Join(toStringSlice(ids), ",")
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, spotifyURL, nil)
-	if err != nil {
-		return err
-	}
+	return c.modifyLibrary(ctx, "shows", true, ids...)
+}
 
-	return c.execute(req, nil, http.StatusOK)
+// RemoveShowsForCurrentUser [removes one or more shows] from the current
+// Spotify user's library.  This call requires the [ScopeUserLibraryModify]
+// scope.  More than 50 IDs may be passed; they are removed in batches of 50.
+//
+// [removes one or more shows]: https://developer.spotify.com/documentation/web-api/reference/remove-shows-user
+func (c *Client) RemoveShowsForCurrentUser(ctx context.Context, ids []ID) error {
+	return c.modifyLibrary(ctx, "shows", false, ids...)
+}
+
+// SaveEpisodesForCurrentUser [saves one or more episodes] to the current
+// Spotify user's library.  This call requires the [ScopeUserLibraryModify]
+// scope.  More than 50 IDs may be passed; they are saved in batches of 50.
+//
+// [saves one or more episodes]: https://developer.spotify.com/documentation/web-api/reference/save-episodes-user
+func (c *Client) SaveEpisodesForCurrentUser(ctx context.Context, ids []ID) error {
+	return c.modifyLibrary(ctx, "episodes", true, ids...)
+}
+
+// RemoveEpisodesForCurrentUser [removes one or more episodes] from the
+// current Spotify user's library.  This call requires the
+// [ScopeUserLibraryModify] scope.  More than 50 IDs may be passed; they are
+// removed in batches of 50.
+//
+// [removes one or more episodes]: https://developer.spotify.com/documentation/web-api/reference/remove-episodes-user
+func (c *Client) RemoveEpisodesForCurrentUser(ctx context.Context, ids []ID) error {
+	return c.modifyLibrary(ctx, "episodes", false, ids...)
 }
 
 // GetEpisode gets an [episode] from a show.
 //
 // [episode]: https://developer.spotify.com/documentation/web-api/reference/get-an-episode
 func (c *Client) GetEpisode(ctx context.Context, id string, opts ...RequestOption) (*EpisodePage, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "episodes/" + id
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
 		spotifyURL += "?" + params
@@ -242,10 +464,54 @@ func (c *Client) GetEpisode(ctx context.Context, id string, opts ...RequestOptio
 
 	var result EpisodePage
 
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &result)
 	if err != nil {
 		return nil, err
 	}
 
 	return &result, nil
 }
+
+// maxEpisodesPerRequest is the number of episode IDs Spotify accepts in a
+// single request for several episodes.
+const maxEpisodesPerRequest = 50
+
+// GetEpisodes gets [several episodes] given their [Spotify ID]s.  The
+// episodes are returned in the order of the IDs.  If an episode is not
+// found, or not available in the market, a nil value is returned in the
+// appropriate position.  More than 50 IDs may be passed; they are fetched in
+// batches of 50.
+//
+// Supported options: [Market].
+//
+// [several episodes]: https://developer.spotify.com/documentation/web-api/reference/get-multiple-episodes
+// [Spotify ID]: https://developer.spotify.com/documentation/web-api/#spotify-uris-and-ids
+func (c *Client) GetEpisodes(ctx context.Context, ids []ID, opts ...RequestOption) ([]*EpisodePage, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := make([]*EpisodePage, 0, len(ids))
+	err = c.fetchChunks(ids, maxEpisodesPerRequest, "episodes", func(chunk []ID) (int, error) {
+		params := processOptions(opts...).urlParams
+		params.Set("ids", strings.Join(toStringSlice(chunk), ","))
+
+		spotifyURL := fmt.Sprintf("%sepisodes?%s", c.baseURL, params.Encode())
+
+		var e struct {
+			Episodes []*EpisodePage `json:"episodes"`
+		}
+
+		if err := c.get(ctx, spotifyURL, &e); err != nil {
+			return 0, err
+		}
+		episodes = append(episodes, e.Episodes...)
+		return len(e.Episodes), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return episodes, nil
+}
@@ -3,7 +3,10 @@ package spotify
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -23,6 +26,62 @@ func TestGetShow(t *testing.T) {
 	}
 }
 
+func TestGetShowLanguages(t *testing.T) {
+	c, s := testClientFile(http.StatusOK, "test_data/get_show_multilanguage.txt")
+	defer s.Close()
+
+	r, err := c.GetShow(context.Background(), "5CfCWKI5pZ28U0uOzXkDHe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"en", "es"}; !reflect.DeepEqual(r.Languages, want) {
+		t.Errorf("Expected show languages %v, got %v", want, r.Languages)
+	}
+	if !r.Explicit {
+		t.Error("Expected show to be explicit")
+	}
+	if len(r.Episodes.Episodes) != 1 {
+		t.Fatal("Invalid data", len(r.Episodes.Episodes))
+	}
+	e := r.Episodes.Episodes[0]
+	if want := []string{"es", "en"}; !reflect.DeepEqual(e.Languages, want) {
+		t.Errorf("Expected episode languages %v, got %v", want, e.Languages)
+	}
+	if !e.Explicit {
+		t.Error("Expected episode to be explicit")
+	}
+}
+
+func TestEpisodePlainDescription(t *testing.T) {
+	c, s := testClientFile(http.StatusOK, "test_data/get_show.txt")
+	defer s.Close()
+
+	r, err := c.GetShow(context.Background(), "1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := r.Episodes.Episodes[1]
+	if !strings.HasPrefix(e.HTMLDescription, "<p>For this episode, Su and I invited the <strong>legendary trader Cobie</strong>") {
+		t.Error("Unexpected HTML description:", e.HTMLDescription)
+	}
+	if e.PlainDescription() != e.Description {
+		t.Error("Expected the plain description to be the description")
+	}
+
+	e.Description = ""
+	want := "For this episode, Su and I invited the legendary trader Cobie, who goes under @CryptoCobain on Twitter. Together, we talked about\n" +
+		"The bull case for Ethereum and its upcoming catalysts\n" +
+		"Whether ETH can flippen BTC\n"
+	if got := e.PlainDescription(); !strings.HasPrefix(got, want) || strings.Contains(got, "<") {
+		t.Errorf("Unexpected plain description: %q", got)
+	}
+
+	show := SimpleShow{HTMLDescription: "<p>Tom &amp; Jerry&nbsp;<br/>Cartoons</p>"}
+	if got := show.PlainDescription(); got != "Tom & Jerry\nCartoons" {
+		t.Errorf("Unexpected plain description: %q", got)
+	}
+}
+
 func TestGetShowEpisodes(t *testing.T) {
 	c, s := testClientFile(http.StatusOK, "test_data/get_show_episodes.txt")
 	defer s.Close()
@@ -82,3 +141,83 @@ func TestGetEpisode(t *testing.T) {
 		t.Error("Invalid data:", r.ID)
 	}
 }
+
+func TestEpisodeRestrictions(t *testing.T) {
+	c, s := testClientString(http.StatusOK, `{
+		"id": "2DSKnz9Hqm1tKimcXqcMJD",
+		"is_playable": false,
+		"restrictions": { "reason": "payment_required" },
+		"type": "episode"
+	}`)
+	defer s.Close()
+
+	r, err := c.GetEpisode(context.Background(), "2DSKnz9Hqm1tKimcXqcMJD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Restrictions == nil || r.Restrictions.Reason != RestrictionPaymentRequired {
+		t.Fatalf("Expected a payment restriction, got %+v", r.Restrictions)
+	}
+	if !r.Restrictions.IsPaymentRequired() || r.Restrictions.IsMarketRestricted() || r.Restrictions.IsExplicitRestricted() {
+		t.Error("Wrong restriction predicates for", r.Restrictions.Reason)
+	}
+
+	var none *EpisodeRestriction
+	if none.IsMarketRestricted() || none.IsProductRestricted() || none.IsExplicitRestricted() || none.IsPaymentRequired() {
+		t.Error("Expected no restriction for a nil EpisodeRestriction")
+	}
+}
+
+func TestGetShowTotalEpisodes(t *testing.T) {
+	c, s := testClientFile(http.StatusOK, "test_data/get_show.txt")
+	defer s.Close()
+
+	r, err := c.GetShow(context.Background(), "1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.TotalEpisodes != 25 {
+		t.Errorf("Expected 25 episodes, got %d", r.TotalEpisodes)
+	}
+}
+
+func TestGetShowsAndEpisodesInChunks(t *testing.T) {
+	testChunks(t, chunkTest{
+		key:    "shows",
+		size:   maxShowsPerRequest,
+		n:      70,
+		market: CountryUnitedKingdom,
+		item: func(id string) string {
+			return fmt.Sprintf(`{ "id": "%s", "total_episodes": 3 }`, id)
+		},
+		get: func(c *Client, ids []ID) ([]ID, error) {
+			shows, err := c.GetShows(context.Background(), ids, Market(CountryUnitedKingdom))
+			got := make([]ID, len(shows))
+			for i, show := range shows {
+				if show != nil {
+					got[i] = show.ID
+					if show.TotalEpisodes != 3 {
+						t.Errorf("Expected 3 episodes of %s, got %d", show.ID, show.TotalEpisodes)
+					}
+				}
+			}
+			return got, err
+		},
+	})
+	testChunks(t, chunkTest{
+		key:    "episodes",
+		size:   maxEpisodesPerRequest,
+		n:      70,
+		market: CountryUnitedKingdom,
+		get: func(c *Client, ids []ID) ([]ID, error) {
+			episodes, err := c.GetEpisodes(context.Background(), ids, Market(CountryUnitedKingdom))
+			got := make([]ID, len(episodes))
+			for i, episode := range episodes {
+				if episode != nil {
+					got[i] = episode.ID
+				}
+			}
+			return got, err
+		},
+	})
+}
@@ -9,8 +9,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -27,6 +30,9 @@ const (
 	// this format.
 	TimestampLayout = "2006-01-02T15:04:05Z"
 
+	// defaultBaseURL is the base URL of the Spotify Web API.
+	defaultBaseURL = "https://api.spotify.com/v1/"
+
 	// defaultRetryDurationS helps us fix an apparent server bug whereby we will
 	// be told to retry but not be given a wait-interval.
 	defaultRetryDuration = time.Second * 5
@@ -39,22 +45,69 @@ type Client struct {
 	baseURL string
 
 	autoRetry      bool
+	maxRetries     int
 	acceptLanguage string
+
+	noAutoChunk bool
+
+	defaultAdditionalTypes []AdditionalType
+
+	defaultMarket  string
+	marketFromUser bool
+	// userMarket caches the current user's country once it has been read for
+	// WithMarketFromUser; userMarketFetched records that it has been read,
+	// since the country can be empty.
+	userMarketMu      sync.Mutex
+	userMarket        string
+	userMarketFetched bool
+
+	deprecationCallback func(endpoint string, sunset time.Time)
 }
 
 type ClientOption func(client *Client)
 
 // WithRetry configures the Spotify API client to automatically retry requests that fail due to rate limiting.
+// Before retrying, the client waits for the delay given by the Retry-After
+// header of the response, unless the request's context would expire first, in
+// which case the rate limit [Error] is returned right away.  Use
+// [WithMaxRetries] to limit the number of retries.
 func WithRetry(shouldRetry bool) ClientOption {
 	return func(client *Client) {
 		client.autoRetry = shouldRetry
 	}
 }
 
+// WithMaxRetries limits the number of times that a client configured with
+// [WithRetry] retries a rate-limited request.  Once the limit is reached, the
+// rate limit [Error] is returned.  By default, or if n is zero, requests are
+// retried until they succeed or their context is done.
+func WithMaxRetries(n int) ClientOption {
+	return func(client *Client) {
+		client.maxRetries = n
+	}
+}
+
+// WithoutAutoChunk disables the splitting of large inputs into several
+// requests.  Methods such as [Client.AddTracksToPlaylist] and
+// [Client.GetArtists] normally split inputs that exceed Spotify's limit per
+// request into batches; with this option they instead return a
+// [TooManyIDsError] without making any request, leaving the caller in control
+// of request boundaries.
+func WithoutAutoChunk() ClientOption {
+	return func(client *Client) {
+		client.noAutoChunk = true
+	}
+}
+
 // WithBaseURL provides an alternative base url to use for requests to the Spotify API. This can be used to connect to a
-// staging or other alternative environment.
+// staging or other alternative environment, a proxy, or a mock server in tests.  The URL takes the place of
+// "https://api.spotify.com/v1/" for every endpoint, including the links to further pages of results returned by
+// Spotify, and a trailing slash is added if it is missing.
 func WithBaseURL(url string) ClientOption {
 	return func(client *Client) {
+		if !strings.HasSuffix(url, "/") {
+			url += "/"
+		}
 		client.baseURL = url
 	}
 }
@@ -66,13 +119,70 @@ func WithAcceptLanguage(lang string) ClientOption {
 	}
 }
 
+// WithDefaultAdditionalTypes configures the item types that [Client.GetPlaylistItems]
+// requests when no [AdditionalTypes] option is given for a call.  Without this
+// option both episodes and tracks are requested.  Apps that only deal with
+// music can use WithDefaultAdditionalTypes(TrackAdditionalType) to avoid
+// receiving episodes.
+func WithDefaultAdditionalTypes(types ...AdditionalType) ClientOption {
+	return func(client *Client) {
+		client.defaultAdditionalTypes = types
+	}
+}
+
+// WithDefaultMarket configures a market, given as an [ISO 3166-1 alpha-2]
+// country code, that is requested from every endpoint that supports the
+// [Market] option when no Market option is given for a call.
+//
+// [ISO 3166-1 alpha-2]: https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2
+func WithDefaultMarket(code string) ClientOption {
+	return func(client *Client) {
+		client.defaultMarket = code
+	}
+}
+
+// WithMarketFromUser configures the client to use the current user's country
+// as the default market, like [WithDefaultMarket].  The country is read with
+// [Client.CurrentUser] the first time an endpoint that supports the [Market]
+// option without a [Market] option of its own is called, and cached for the
+// lifetime of the client, so it only costs a single request.  If the country
+// can't be read, that call fails and the next one tries again.
+//
+// Reading the country requires the [ScopeUserReadPrivate] scope; without it,
+// Spotify reports no country, and no default market is used.  If a default
+// market is also configured with [WithDefaultMarket], that market is used
+// instead.
+func WithMarketFromUser() ClientOption {
+	return func(client *Client) {
+		client.marketFromUser = true
+	}
+}
+
+// WithDeprecationCallback configures a function that is called when Spotify
+// marks a response with a Deprecation or Sunset header, which it does for
+// endpoints that are being phased out.  The function receives the URL of the
+// endpoint, without its query, and the time given by the Sunset header, which
+// is zero if there is none.  It is called once per request, from the
+// goroutine making the request, and doesn't change how the request is
+// handled otherwise.
+func WithDeprecationCallback(fn func(endpoint string, sunset time.Time)) ClientOption {
+	return func(client *Client) {
+		client.deprecationCallback = fn
+	}
+}
+
 // New returns a client for working with the Spotify Web API.
 // The provided httpClient must provide Authentication with the requests.
 // The auth package may be used to generate a suitable client.
+//
+// The client never sets the Accept-Encoding header itself, so that the
+// [net/http.Transport] can request gzip-compressed responses and decompress
+// them transparently.  If httpClient uses a custom [net/http.RoundTripper] that
+// doesn't wrap a [net/http.Transport], it must handle decompression itself.
 func New(httpClient *http.Client, opts ...ClientOption) *Client {
 	c := &Client{
 		http:    httpClient,
-		baseURL: "https://api.spotify.com/v1/",
+		baseURL: defaultBaseURL,
 	}
 
 	for _, opt := range opts {
@@ -95,15 +205,34 @@ func (id *ID) String() string {
 }
 
 // Numeric is a convenience type for handling numbers sent as either integers or floats.
-type Numeric int
+// It is 64 bits wide on all platforms, so that large counts, such as the
+// number of followers of a popular artist, don't overflow on 32-bit platforms.
+type Numeric int64
 
 // UnmarshalJSON unmarshals a JSON number (float or int) into the Numeric type.
+// Integers are decoded exactly, and the fractional part of floats is
+// discarded.  Numbers outside the range of an int64 result in an error.
 func (n *Numeric) UnmarshalJSON(data []byte) error {
-	var f float64
-	if err := json.Unmarshal(data, &f); err != nil {
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+	if num == "" {
+		// null leaves the value unchanged
+		return nil
+	}
+	if i, err := strconv.ParseInt(string(num), 10, 64); err == nil {
+		*n = Numeric(i)
+		return nil
+	}
+	f, err := strconv.ParseFloat(string(num), 64)
+	if err != nil {
 		return err
 	}
-	*n = Numeric(int(f))
+	if f >= math.MaxInt64 || f < math.MinInt64 {
+		return fmt.Errorf("spotify: number %s is out of range", num)
+	}
+	*n = Numeric(f)
 	return nil
 }
 
@@ -148,8 +277,12 @@ type Error struct {
 	Message string `json:"message"`
 	// The HTTP status code.
 	Status int `json:"status"`
+	// Reason is reported by the player endpoints, such as [Client.Play],
+	// to explain why a command failed.  It is empty for other errors.
+	Reason PlayerErrorReason `json:"reason"`
 	// RetryAfter contains the time before which client should not retry a
 	// rate-limited request, calculated from the Retry-After header, when present.
+	// See [Error.RetryDelay] for the remaining time to wait.
 	RetryAfter time.Time `json:"-"`
 }
 
@@ -163,12 +296,31 @@ func (e Error) HTTPStatus() int {
 	return e.Status
 }
 
+// RetryDelay returns how long to wait before retrying a rate-limited request,
+// according to the Retry-After header of the response.  It returns zero if
+// the response had no Retry-After header, or if that time has already passed.
+func (e Error) RetryDelay() time.Duration {
+	if e.RetryAfter.IsZero() {
+		return 0
+	}
+	if d := time.Until(e.RetryAfter); d > 0 {
+		return d
+	}
+	return 0
+}
+
 // decodeError decodes an Error from an io.Reader.
 func decodeError(resp *http.Response) error {
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
+
+	var retryAfter time.Time
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		retryAfter = time.Now().Add(d)
+	}
+
 	if ctHeader := resp.Header.Get("Content-Type"); ctHeader == "" {
 		msg := string(responseBody)
 		if len(msg) == 0 {
@@ -176,15 +328,17 @@ func decodeError(resp *http.Response) error {
 		}
 
 		return Error{
-			Message: msg,
-			Status:  resp.StatusCode,
+			Message:    msg,
+			Status:     resp.StatusCode,
+			RetryAfter: retryAfter,
 		}
 	}
 
 	if len(responseBody) == 0 {
 		return Error{
-			Message: "server response without body",
-			Status:  resp.StatusCode,
+			Message:    "server response without body",
+			Status:     resp.StatusCode,
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -196,8 +350,9 @@ func decodeError(resp *http.Response) error {
 	err = json.NewDecoder(buf).Decode(&e)
 	if err != nil {
 		return Error{
-			Message: fmt.Sprintf("failed to decode error response %q", responseBody),
-			Status:  resp.StatusCode,
+			Message:    fmt.Sprintf("failed to decode error response %q", responseBody),
+			Status:     resp.StatusCode,
+			RetryAfter: retryAfter,
 		}
 	}
 
@@ -210,9 +365,7 @@ func decodeError(resp *http.Response) error {
 
 		e.E.Message = "server response without error description"
 	}
-	if retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After")); retryAfter != 0 {
-		e.E.RetryAfter = time.Now().Add(time.Duration(retryAfter) * time.Second)
-	}
+	e.E.RetryAfter = retryAfter
 
 	return e.E
 }
@@ -237,10 +390,21 @@ func isFailure(code int, validCodes []int) bool {
 // status codes that will be treated as success. Note that we allow all 200s
 // even if there are additional success codes that represent success.
 func (c *Client) execute(req *http.Request, result interface{}, needsStatus ...int) error {
+	if err := checkMarketParam(req.URL); err != nil {
+		return err
+	}
 	if c.acceptLanguage != "" {
 		req.Header.Set("Accept-Language", c.acceptLanguage)
 	}
-	for {
+	for retries := 0; ; retries++ {
+		if retries > 0 && req.GetBody != nil {
+			// the body of the previous attempt has been consumed
+			body, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			req.Body = body
+		}
 		resp, err := c.http.Do(req)
 		if err != nil {
 			return err
@@ -249,14 +413,11 @@ func (c *Client) execute(req *http.Request, result interface{}, needsStatus ...i
 
 		if c.autoRetry &&
 			isFailure(resp.StatusCode, needsStatus) &&
-			shouldRetry(resp.StatusCode) {
-			select {
-			case <-req.Context().Done():
-				// If the context is cancelled, return the original error
-			case <-time.After(retryDuration(resp)):
-				continue
-			}
+			shouldRetry(resp.StatusCode) &&
+			c.waitToRetry(req.Context(), resp, retries) {
+			continue
 		}
+		c.checkDeprecation(req, resp)
 		if resp.StatusCode == http.StatusNoContent {
 			return nil
 		}
@@ -266,6 +427,8 @@ func (c *Client) execute(req *http.Request, result interface{}, needsStatus ...i
 			return decodeError(resp)
 		}
 
+		// a nil result ignores the body, which is empty for endpoints such
+		// as FollowPlaylist that respond with a bare status
 		if result != nil {
 			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
 				return err
@@ -276,27 +439,97 @@ func (c *Client) execute(req *http.Request, result interface{}, needsStatus ...i
 	return nil
 }
 
+// checkDeprecation calls the deprecation callback, if there is one, when resp
+// has a Deprecation or Sunset header.
+func (c *Client) checkDeprecation(req *http.Request, resp *http.Response) {
+	if c.deprecationCallback == nil {
+		return
+	}
+	deprecation, sunset := resp.Header.Get("Deprecation"), resp.Header.Get("Sunset")
+	if deprecation == "" && sunset == "" {
+		return
+	}
+
+	endpoint := *req.URL
+	endpoint.RawQuery = ""
+	var sunsetTime time.Time
+	if t, err := http.ParseTime(sunset); err == nil {
+		sunsetTime = t
+	}
+	c.deprecationCallback(endpoint.String(), sunsetTime)
+}
+
 func retryDuration(resp *http.Response) time.Duration {
-	raw := resp.Header.Get("Retry-After")
-	if raw == "" {
-		return defaultRetryDuration
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return d
 	}
-	seconds, err := strconv.ParseInt(raw, 10, 32)
-	if err != nil {
-		return defaultRetryDuration
+	return defaultRetryDuration
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date.  It returns false if the value is empty
+// or invalid.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 32); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// waitToRetry waits for the delay given by the Retry-After header of resp,
+// and reports whether the request should then be retried.  It returns false
+// without waiting once the request has been retried as many times as allowed
+// by [WithMaxRetries], or if ctx would expire before the delay ends.
+func (c *Client) waitToRetry(ctx context.Context, resp *http.Response, retries int) bool {
+	if c.maxRetries > 0 && retries >= c.maxRetries {
+		return false
+	}
+	wait := retryDuration(resp)
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return false
+	}
+	select {
+	case <-ctx.Done():
+		// If the context is cancelled, return the original error
+		return false
+	case <-time.After(wait):
+		return true
 	}
-	return time.Duration(seconds) * time.Second
 }
 
 func (c *Client) get(ctx context.Context, url string, result interface{}) error {
-	for {
+	return c.getStream(ctx, url, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(result)
+	})
+}
+
+// getStream is like get, but passes the body of a successful response to
+// decode instead of decoding it into a value, so that it can be processed as
+// it is read.  decode isn't called for a 204 response.
+func (c *Client) getStream(ctx context.Context, url string, decode func(body io.Reader) error) error {
+	for retries := 0; ; retries++ {
 		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if c.acceptLanguage != "" {
-			req.Header.Set("Accept-Language", c.acceptLanguage)
-		}
 		if err != nil {
 			return err
 		}
+		if err := checkMarketParam(req.URL); err != nil {
+			return err
+		}
+		if c.acceptLanguage != "" {
+			req.Header.Set("Accept-Language", c.acceptLanguage)
+		}
 		resp, err := c.http.Do(req)
 		if err != nil {
 			return err
@@ -304,14 +537,10 @@ func (c *Client) get(ctx context.Context, url string, result interface{}) error
 
 		defer resp.Body.Close()
 
-		if resp.StatusCode == http.StatusTooManyRequests && c.autoRetry {
-			select {
-			case <-ctx.Done():
-				// If the context is cancelled, return the original error
-			case <-time.After(retryDuration(resp)):
-				continue
-			}
+		if resp.StatusCode == http.StatusTooManyRequests && c.autoRetry && c.waitToRetry(ctx, resp, retries) {
+			continue
 		}
+		c.checkDeprecation(req, resp)
 		if resp.StatusCode == http.StatusNoContent {
 			return nil
 		}
@@ -319,31 +548,31 @@ func (c *Client) get(ctx context.Context, url string, result interface{}) error
 			return decodeError(resp)
 		}
 
-		return json.NewDecoder(resp.Body).Decode(result)
+		return decode(resp.Body)
 	}
 }
 
-// NewReleases gets a list of new album releases featured in Spotify.
-// Supported options: Country, Limit, Offset
+// NewReleases gets a list of new album releases featured in Spotify, as shown
+// in the "Browse" tab of the Spotify player.  If the response holds no albums,
+// an empty page is returned.
+//
+// Supported options: [Country], [Limit], [Offset].
 func (c *Client) NewReleases(ctx context.Context, opts ...RequestOption) (albums *SimpleAlbumPage, err error) {
 	spotifyURL := c.baseURL + "browse/new-releases"
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
 		spotifyURL += "?" + params
 	}
 
-	var objmap map[string]*json.RawMessage
-	err = c.get(ctx, spotifyURL, &objmap)
-	if err != nil {
-		return nil, err
+	var wrapper struct {
+		Albums SimpleAlbumPage `json:"albums"`
 	}
 
-	var result SimpleAlbumPage
-	err = json.Unmarshal(*objmap["albums"], &result)
+	err = c.get(ctx, spotifyURL, &wrapper)
 	if err != nil {
 		return nil, err
 	}
 
-	return &result, nil
+	return &wrapper.Albums, nil
 }
 
 // Token gets the client's current token.
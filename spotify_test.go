@@ -1,12 +1,16 @@
 package spotify
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -27,10 +31,7 @@ func testClient(code int, body io.Reader, validators ...func(*http.Request)) (*C
 			closer.Close()
 		}
 	}))
-	client := &Client{
-		http:    http.DefaultClient,
-		baseURL: server.URL + "/",
-	}
+	client := New(http.DefaultClient, WithBaseURL(server.URL))
 	return client, server
 }
 
@@ -51,6 +52,199 @@ func testClientFile(code int, filename string, validators ...func(*http.Request)
 	return testClient(code, f, validators...)
 }
 
+// Environment variables that make testClientRecorder record fixtures from the
+// live Web API instead of replaying them.  SPOTIFY_TOKEN must hold a valid
+// OAuth2 access token with whatever scopes the recorded endpoints require.
+const (
+	recordFixturesEnv = "SPOTIFY_RECORD_FIXTURES"
+	recordTokenEnv    = "SPOTIFY_TOKEN"
+)
+
+// Returns a client whose requests are served from the specified fixture file,
+// like testClientFile.  If the SPOTIFY_RECORD_FIXTURES environment variable is
+// set, requests are instead forwarded to the live Web API, and each response
+// body is written to the fixture file before being returned, so that fixtures
+// can be regenerated by re-running the test that uses them:
+//
+//	SPOTIFY_RECORD_FIXTURES=1 SPOTIFY_TOKEN=... go test -run TestFeaturedPlaylists
+func testClientRecorder(t *testing.T, code int, filename string, validators ...func(*http.Request)) (*Client, *httptest.Server) {
+	if os.Getenv(recordFixturesEnv) == "" {
+		return testClientFile(code, filename, validators...)
+	}
+
+	token := os.Getenv(recordTokenEnv)
+	if token == "" {
+		t.Fatalf("%s is set, but %s is empty", recordFixturesEnv, recordTokenEnv)
+	}
+
+	return testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, v := range validators {
+			v(r)
+		}
+
+		req, err := http.NewRequestWithContext(r.Context(), r.Method, "https://api.spotify.com/v1/"+strings.TrimPrefix(r.URL.RequestURI(), "/"), r.Body)
+		if err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		if ct := r.Header.Get("Content-Type"); ct != "" {
+			req.Header.Set("Content-Type", ct)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		if resp.StatusCode != code {
+			t.Errorf("Recording %s: expected HTTP %d, got %d", filename, code, resp.StatusCode)
+		}
+		if err := os.WriteFile(filename, body, 0o644); err != nil {
+			t.Error(err)
+		}
+		t.Logf("Recorded %s into %s", r.URL.RequestURI(), filename)
+
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(body)
+	}))
+}
+
+// Returns a client whose requests are served by the specified handler,
+// for tests that need to respond differently to different endpoints.
+func testClientHandler(handler http.Handler) (*Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client := New(http.DefaultClient, WithBaseURL(server.URL))
+	return client, server
+}
+
+// chunkTest describes a method that gets several items by ID, which it
+// fetches from Spotify in chunks, for testChunks.
+type chunkTest struct {
+	// key holds the items in Spotify's response, such as "albums".
+	key string
+	// size is the number of IDs Spotify accepts per request, and n the
+	// number of IDs to get.
+	size, n int
+	// market, if set, is the market that every request must have.
+	market string
+	// item returns the JSON of the item with the given ID.  If it is nil,
+	// the item is an object with just the ID.
+	item func(id string) string
+	// get calls the method, returning the ID that each item was requested
+	// with, or "" for a nil item.
+	get func(c *Client, ids []ID) ([]ID, error)
+}
+
+// testChunks checks that tt.get requests its IDs in chunks of tt.size, and
+// returns the items in the order requested, with nil for the IDs Spotify
+// doesn't know.  Every seventh ID, starting with the fourth, is one of those.
+func testChunks(t *testing.T, tt chunkTest) {
+	t.Helper()
+
+	var batches []int
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if market := r.URL.Query().Get("market"); market != tt.market {
+			t.Errorf("Expected market '%s', got '%s'", tt.market, market)
+		}
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		batches = append(batches, len(ids))
+		items := make([]string, len(ids))
+		for i, id := range ids {
+			switch {
+			case strings.HasPrefix(id, "missing"):
+				items[i] = "null"
+			case tt.item != nil:
+				items[i] = tt.item(id)
+			default:
+				items[i] = fmt.Sprintf(`{ "id": "%s" }`, id)
+			}
+		}
+		fmt.Fprintf(w, `{ "%s": [ %s ] }`, tt.key, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	ids := make([]ID, tt.n)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("id%d", i))
+		if i%7 == 3 {
+			ids[i] = ID(fmt.Sprintf("missing%d", i))
+		}
+	}
+
+	got, err := tt.get(client, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []int
+	for n := tt.n; n > 0; n -= tt.size {
+		if n < tt.size {
+			want = append(want, n)
+		} else {
+			want = append(want, tt.size)
+		}
+	}
+	if !reflect.DeepEqual(batches, want) {
+		t.Errorf("Expected batches of %v IDs, got %v", want, batches)
+	}
+	if len(got) != len(ids) {
+		t.Fatalf("Expected %d %s, got %d", len(ids), tt.key, len(got))
+	}
+	for i, id := range got {
+		want := ids[i]
+		if strings.HasPrefix(string(want), "missing") {
+			want = ""
+		}
+		if id != want {
+			t.Errorf("Expected '%s' at position %d, got '%s'", want, i, id)
+		}
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	var acceptEncoding string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		status, body := http.StatusOK, `{ "id": "wizzler", "display_name": "Ronald Pompa" }`
+		if r.URL.Path == "/users/missing" {
+			status, body = http.StatusNotFound, `{ "error": { "status": 404, "message": "No such user" } }`
+		}
+		w.WriteHeader(status)
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(body))
+		gz.Close()
+	}))
+	defer server.Close()
+
+	user, err := client.GetUsersPublicProfile(context.Background(), "wizzler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acceptEncoding != "gzip" {
+		t.Errorf("Expected the transport to request gzip, got Accept-Encoding '%s'", acceptEncoding)
+	}
+	if user.DisplayName != "Ronald Pompa" {
+		t.Error("Expected the gzipped body to be decoded, got", user.DisplayName)
+	}
+
+	_, err = client.GetUsersPublicProfile(context.Background(), "missing")
+	if se, ok := err.(Error); !ok || se.Message != "No such user" {
+		t.Error("Expected the gzipped error to be decoded, got", err)
+	}
+}
+
 func TestNewReleases(t *testing.T) {
 	c, s := testClientFile(http.StatusOK, "test_data/new_releases.txt")
 	defer s.Close()
@@ -67,6 +261,19 @@ func TestNewReleases(t *testing.T) {
 	}
 }
 
+func TestNewReleasesWithoutAlbums(t *testing.T) {
+	c, s := testClientString(http.StatusOK, `{}`)
+	defer s.Close()
+
+	r, err := c.NewReleases(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Albums) != 0 {
+		t.Errorf("Expected no albums, got %d", len(r.Albums))
+	}
+}
+
 func TestNewReleasesRateLimitExceeded(t *testing.T) {
 	t.Parallel()
 	handlers := []http.HandlerFunc{
@@ -225,4 +432,286 @@ func TestDecode429Error(t *testing.T) {
 	if err.Error() != "spotify: Too many requests [429]" {
 		t.Error("Unexpected error message:", err.Error())
 	}
+	if d := err.(Error).RetryDelay(); d <= time.Second || d > 2*time.Second {
+		t.Error("Unexpected retry delay:", d)
+	}
+}
+
+func TestRetryWaitsForRetryAfter(t *testing.T) {
+	t.Parallel()
+	var requests []time.Time
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		if len(requests) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = io.WriteString(w, `{ "id": "track" }`)
+	}))
+	defer server.Close()
+	WithRetry(true)(client)
+
+	if _, err := client.GetTrack(context.Background(), "track"); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected the request to be retried once, got %d requests", len(requests))
+	}
+	if waited := requests[1].Sub(requests[0]); waited < 2*time.Second {
+		t.Errorf("Expected to wait 2s before retrying, waited %s", waited)
+	}
+}
+
+func TestWithMaxRetries(t *testing.T) {
+	var bodies []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	WithRetry(true)(client)
+	WithMaxRetries(2)(client)
+
+	_, err := client.AddTracksToPlaylist(context.Background(), "playlist", "track")
+	var spotifyError Error
+	if !errors.As(err, &spotifyError) || spotifyError.Status != http.StatusTooManyRequests {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if len(bodies) != 3 {
+		t.Fatalf("Expected 1 request and 2 retries, got %d requests", len(bodies))
+	}
+	for _, body := range bodies {
+		if body != `{"uris":["spotify:track:track"]}` {
+			t.Errorf("Expected every attempt to send the tracks, got '%s'", body)
+		}
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	var requests int
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	WithRetry(true)(client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.GetTrack(ctx, "track")
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("Expected not to wait beyond the deadline")
+	}
+	var spotifyError Error
+	if !errors.As(err, &spotifyError) || spotifyError.Status != http.StatusTooManyRequests {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if d := spotifyError.RetryDelay(); d <= 9*time.Second {
+		t.Error("Unexpected retry delay:", d)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		fmt.Fprint(w, `{ "items": [], "next": "https://api.spotify.com/v1/me/tracks?offset=20&limit=20", "total": 40 }`)
+	}))
+	defer server.Close()
+
+	for _, base := range []string{server.URL + "/proxy/v1", server.URL + "/proxy/v1/"} {
+		paths = nil
+		client := New(http.DefaultClient, WithBaseURL(base))
+		page, err := client.CurrentUsersTracks(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := client.NextPage(context.Background(), page); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"/proxy/v1/me/tracks", "/proxy/v1/me/tracks?offset=20&limit=20"}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("%s: expected requests to %v, got %v", base, want, paths)
+		}
+	}
+}
+
+func TestWithDefaultMarket(t *testing.T) {
+	var market string
+	client, server := testClientString(http.StatusOK, `{ "id": "track" }`, func(r *http.Request) {
+		market = r.URL.Query().Get("market")
+	})
+	defer server.Close()
+	WithDefaultMarket(CountryUnitedKingdom)(client)
+
+	if _, err := client.GetTrack(context.Background(), "track"); err != nil {
+		t.Fatal(err)
+	}
+	if market != CountryUnitedKingdom {
+		t.Errorf("Expected default market %s, got '%s'", CountryUnitedKingdom, market)
+	}
+}
+
+func TestWithMarketFromUser(t *testing.T) {
+	var meCalls int
+	var markets []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		meCalls++
+		_, _ = io.WriteString(w, `{ "id": "user", "country": "GB" }`)
+	})
+	mux.HandleFunc("/tracks/", func(w http.ResponseWriter, r *http.Request) {
+		markets = append(markets, r.URL.Query().Get("market"))
+		_, _ = io.WriteString(w, `{ "id": "track" }`)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+	WithMarketFromUser()(client)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTrack(context.Background(), "track"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.GetTrack(context.Background(), "track", Market("SE")); err != nil {
+		t.Fatal(err)
+	}
+	if meCalls != 1 {
+		t.Errorf("Expected the user's country to be read once, got %d calls", meCalls)
+	}
+	if len(markets) != 3 || markets[0] != "GB" || markets[1] != "GB" || markets[2] != "SE" {
+		t.Errorf("Unexpected markets: %v", markets)
+	}
+}
+
+func TestWithMarketFromUserWithoutCountry(t *testing.T) {
+	var meCalls int
+	var markets []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		meCalls++
+		_, _ = io.WriteString(w, `{ "id": "user" }`)
+	})
+	mux.HandleFunc("/tracks/", func(w http.ResponseWriter, r *http.Request) {
+		markets = append(markets, r.URL.Query().Get("market"))
+		_, _ = io.WriteString(w, `{ "id": "track" }`)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+	WithMarketFromUser()(client)
+
+	// an explicit market doesn't need the user's country
+	if _, err := client.GetTrack(context.Background(), "track", Market("SE")); err != nil {
+		t.Fatal(err)
+	}
+	if meCalls != 0 {
+		t.Errorf("Expected no request for the user's country, got %d", meCalls)
+	}
+
+	// an empty country is only read once
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetTrack(context.Background(), "track"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if meCalls != 1 {
+		t.Errorf("Expected the user's country to be read once, got %d calls", meCalls)
+	}
+	if !reflect.DeepEqual(markets, []string{"SE", "", ""}) {
+		t.Errorf("Unexpected markets: %v", markets)
+	}
+}
+
+func TestWithDeprecationCallback(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/recommendations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", "Sat, 30 Nov 2024 23:59:59 GMT")
+		_, _ = io.WriteString(w, `{ "tracks": [] }`)
+	})
+	mux.HandleFunc("/audio-features/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@1732924799")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/tracks/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{ "id": "track" }`)
+	})
+	client, server := testClientHandler(mux)
+	defer server.Close()
+
+	var endpoints []string
+	var sunsets []time.Time
+	WithDeprecationCallback(func(endpoint string, sunset time.Time) {
+		endpoints = append(endpoints, endpoint)
+		sunsets = append(sunsets, sunset)
+	})(client)
+
+	if _, err := client.GetRecommendations(context.Background(), Seeds{Tracks: []ID{"track"}}, nil, Limit(5)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetTrackAudioFeatures(context.Background(), "track"); err == nil {
+		t.Error("Expected the error response to be returned")
+	}
+	if _, err := client.GetTrack(context.Background(), "track"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{server.URL + "/recommendations", server.URL + "/audio-features/track"}
+	if len(endpoints) != 2 || endpoints[0] != expected[0] || endpoints[1] != expected[1] {
+		t.Fatalf("Expected callbacks for %v, got %v", expected, endpoints)
+	}
+	if want := time.Date(2024, 11, 30, 23, 59, 59, 0, time.UTC); !sunsets[0].Equal(want) {
+		t.Errorf("Expected sunset %v, got %v", want, sunsets[0])
+	}
+	if !sunsets[1].IsZero() {
+		t.Errorf("Expected no sunset, got %v", sunsets[1])
+	}
+}
+
+func TestNumericUnmarshal(t *testing.T) {
+	testTable := []struct {
+		JSON     string
+		Expected Numeric
+	}{
+		{`42`, 42},
+		{`42.9`, 42},
+		{`1e3`, 1000},
+		{`-7`, -7},
+		{`3000000000`, 3000000000},
+		{`9007199254740993`, 9007199254740993}, // 2^53 + 1, which a float64 can't hold
+		{`null`, 0},
+	}
+	for _, tt := range testTable {
+		var n Numeric
+		if err := json.Unmarshal([]byte(tt.JSON), &n); err != nil {
+			t.Errorf("%s: %v", tt.JSON, err)
+			continue
+		}
+		if n != tt.Expected {
+			t.Errorf("%s: expected %d, got %d", tt.JSON, tt.Expected, n)
+		}
+	}
+
+	for _, invalid := range []string{`1e20`, `true`} {
+		var n Numeric
+		if err := json.Unmarshal([]byte(invalid), &n); err == nil {
+			t.Errorf("%s: expected an error, got %d", invalid, n)
+		}
+	}
+
+	var artist FullArtist
+	if err := json.Unmarshal([]byte(`{ "followers": { "total": 4294967296 } }`), &artist); err != nil {
+		t.Fatal(err)
+	}
+	if count := artist.FollowerCount(); count != 4294967296 {
+		t.Errorf("Expected 4294967296 followers, got %d", count)
+	}
 }
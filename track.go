@@ -2,7 +2,6 @@ package spotify
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,7 +18,10 @@ type SimpleTrack struct {
 	Album   SimpleAlbum    `json:"album"`
 	Artists []SimpleArtist `json:"artists"`
 	// A list of the countries in which the track can be played,
-	// identified by their [ISO 3166-1 alpha-2] codes.
+	// identified by their [ISO 3166-1 alpha-2] codes.  Spotify omits this
+	// list when the request specifies a [Market], and reports
+	// [FullTrack.IsPlayable] instead, so an empty list does not mean that the
+	// track is unavailable everywhere.  See [FullTrack.Playable].
 	//
 	// [ISO 3166-1 alpha=2]: https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2
 	AvailableMarkets []string `json:"available_markets"`
@@ -98,6 +100,42 @@ type FullTrack struct {
 	LinkedFrom *LinkedFromInfo `json:"linked_from"`
 }
 
+// RequestedID returns the ID of the track that was asked for.  When [Track
+// Relinking] is applied, Spotify returns a different track that is playable
+// in the requested market, with the original track in LinkedFrom; RequestedID
+// then returns the ID of the original track, so the result can be matched up
+// with the request.  Otherwise it returns the track's own ID.
+//
+// [Track Relinking]: https://developer.spotify.com/documentation/general/guides/track-relinking-guide/
+func (t *FullTrack) RequestedID() ID {
+	if t.LinkedFrom != nil && t.LinkedFrom.ID != "" {
+		return t.LinkedFrom.ID
+	}
+	return t.ID
+}
+
+// Playable reports whether the track can be played in the specified market,
+// given as an [ISO 3166-1 alpha-2] country code.
+//
+// When the track was requested with a [Market] option, Spotify omits
+// AvailableMarkets and reports IsPlayable for that market instead; in that case
+// IsPlayable is consulted and the market argument is assumed to be the one that
+// was requested.  Otherwise, the market is looked up in AvailableMarkets.  If
+// neither is available, Playable returns false.
+//
+// [ISO 3166-1 alpha-2]: https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2
+func (t *FullTrack) Playable(market string) bool {
+	if len(t.AvailableMarkets) == 0 {
+		return t.IsPlayable != nil && *t.IsPlayable
+	}
+	for _, m := range t.AvailableMarkets {
+		if m == market {
+			return true
+		}
+	}
+	return false
+}
+
 // PlaylistTrack contains info about a track in a playlist.
 type PlaylistTrack struct {
 	// The date and time the track was added to the playlist. You can use
@@ -105,7 +143,8 @@ type PlaylistTrack struct {
 	// Warning: very old playlists may not populate this value.
 	AddedAt string `json:"added_at"`
 	// The Spotify user who added the track to the playlist.
-	// Warning: vary old playlists may not populate this value.
+	// Warning: very old playlists may not populate this value.  When
+	// Spotify reports it as null, AddedBy is left as the zero User.
 	AddedBy User `json:"added_by"`
 	// Whether this track is a local file or not.
 	IsLocal bool `json:"is_local"`
@@ -113,6 +152,12 @@ type PlaylistTrack struct {
 	Track FullTrack `json:"track"`
 }
 
+// AddedByID returns the ID of the user who added the track to the playlist, or
+// an empty string if Spotify didn't report who added it.
+func (t *PlaylistTrack) AddedByID() string {
+	return t.AddedBy.ID
+}
+
 // SavedTrack provides info about a track saved to a user's account.
 type SavedTrack struct {
 	// The date and time the track was saved, represented as an ISO 8601 UTC
@@ -135,6 +180,11 @@ func (t *SimpleTrack) TimeDuration() time.Duration {
 // [single track]: https://developer.spotify.com/documentation/web-api/reference/get-track
 // [Spotify ID]: https://developer.spotify.com/documentation/web-api/#spotify-uris-and-ids
 func (c *Client) GetTrack(ctx context.Context, id ID, opts ...RequestOption) (*FullTrack, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "tracks/" + string(id)
 
 	var t FullTrack
@@ -143,7 +193,7 @@ func (c *Client) GetTrack(ctx context.Context, id ID, opts ...RequestOption) (*F
 		spotifyURL += "?" + params
 	}
 
-	err := c.get(ctx, spotifyURL, &t)
+	err = c.get(ctx, spotifyURL, &t)
 	if err != nil {
 		return nil, err
 	}
@@ -151,32 +201,47 @@ func (c *Client) GetTrack(ctx context.Context, id ID, opts ...RequestOption) (*F
 	return &t, nil
 }
 
+// maxTracksPerRequest is the number of track IDs Spotify accepts in a single
+// request for several tracks.
+const maxTracksPerRequest = 50
+
 // GetTracks gets Spotify catalog information for [multiple tracks] based on their
-// Spotify IDs.  It supports up to 50 tracks in a single call.  Tracks are
-// returned in the order requested.  If a track is not found, that position in the
-// result will be nil.  Duplicate ids in the query will result in duplicate
-// tracks in the result.
+// Spotify IDs.  Tracks are returned in the order requested.  If a track is not
+// found, that position in the result will be nil.  Duplicate ids in the query
+// will result in duplicate tracks in the result.  More than 50 IDs may be
+// passed; they are fetched in batches of 50.
+//
+// With a [Market] option, Spotify may return a different track than the one
+// requested, as described for [FullTrack.RequestedID].
 //
 // Supported options: [Market].
 //
 // [multiple tracks]: https://developer.spotify.com/documentation/web-api/reference/get-several-tracks
 func (c *Client) GetTracks(ctx context.Context, ids []ID, opts ...RequestOption) ([]*FullTrack, error) {
-	if len(ids) > 50 {
-		return nil, errors.New("spotify: FindTracks supports up to 50 tracks")
-	}
-
-	params := processOptions(opts...).urlParams
-	params.Set("ids", strings.Join(toStringSlice(ids), ","))
-	spotifyURL := c.baseURL + "tracks?" + params.Encode()
-
-	var t struct {
-		Tracks []*FullTrack `json:"tracks"`
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	err := c.get(ctx, spotifyURL, &t)
+	tracks := make([]*FullTrack, 0, len(ids))
+	err = c.fetchChunks(ids, maxTracksPerRequest, "tracks", func(chunk []ID) (int, error) {
+		params := processOptions(opts...).urlParams
+		params.Set("ids", strings.Join(toStringSlice(chunk), ","))
+		spotifyURL := c.baseURL + "tracks?" + params.Encode()
+
+		var t struct {
+			Tracks []*FullTrack `json:"tracks"`
+		}
+
+		if err := c.get(ctx, spotifyURL, &t); err != nil {
+			return 0, err
+		}
+		tracks = append(tracks, t.Tracks...)
+		return len(t.Tracks), nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return t.Tracks, nil
+	return tracks, nil
 }
@@ -2,6 +2,7 @@ package spotify
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"testing"
 )
@@ -18,6 +19,9 @@ func TestFindTrack(t *testing.T) {
 	if track.Name != "Timber" {
 		t.Errorf("Wanted track Timer, got %s\n", track.Name)
 	}
+	if track.DiscNumber != 1 || track.TrackNumber != 1 {
+		t.Errorf("Wanted disc 1 track 1, got disc %d track %d\n", track.DiscNumber, track.TrackNumber)
+	}
 }
 
 func TestFindTrackWithFloats(t *testing.T) {
@@ -70,3 +74,79 @@ func TestFindTracksNotFound(t *testing.T) {
 		t.Error("Expected nil track (invalid ID) but got valid track")
 	}
 }
+
+func TestFindTrackWithMarket(t *testing.T) {
+	var market string
+	client, server := testClientString(http.StatusOK, `{
+		"id": "1zHlj4dQ8ZAtrayhuDDmkY",
+		"name": "Timber",
+		"is_playable": true
+	}`, func(r *http.Request) {
+		market = r.URL.Query().Get("market")
+	})
+	defer server.Close()
+
+	track, err := client.GetTrack(context.Background(), "1zHlj4dQ8ZAtrayhuDDmkY", Market(CountryUnitedKingdom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if market != CountryUnitedKingdom {
+		t.Errorf("Expected market %s, got %s\n", CountryUnitedKingdom, market)
+	}
+	if len(track.AvailableMarkets) != 0 {
+		t.Error("Expected available markets to be omitted")
+	}
+	if !track.Playable(CountryUnitedKingdom) {
+		t.Error("Expected track to be playable based on is_playable")
+	}
+}
+
+func TestFullTrackPlayable(t *testing.T) {
+	notPlayable := false
+	tests := []struct {
+		name   string
+		track  FullTrack
+		market string
+		want   bool
+	}{
+		{"in available markets", FullTrack{SimpleTrack: SimpleTrack{AvailableMarkets: []string{"GB", "SE"}}}, "SE", true},
+		{"not in available markets", FullTrack{SimpleTrack: SimpleTrack{AvailableMarkets: []string{"GB", "SE"}}}, "US", false},
+		{"is_playable false", FullTrack{IsPlayable: &notPlayable}, "US", false},
+		{"no information", FullTrack{}, "US", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.track.Playable(tt.market); got != tt.want {
+				t.Errorf("Expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetTracksInChunksWithRelinking(t *testing.T) {
+	testChunks(t, chunkTest{
+		key:    "tracks",
+		size:   maxTracksPerRequest,
+		n:      60,
+		market: CountryGermany,
+		item: func(id string) string {
+			if id == "id11" {
+				return fmt.Sprintf(`{ "id": "relinked%s", "is_playable": true, "linked_from": { "id": "%s", "type": "track" } }`, id, id)
+			}
+			return fmt.Sprintf(`{ "id": "%s", "is_playable": true }`, id)
+		},
+		get: func(c *Client, ids []ID) ([]ID, error) {
+			tracks, err := c.GetTracks(context.Background(), ids, Market(CountryGermany))
+			got := make([]ID, len(tracks))
+			for i, track := range tracks {
+				if track != nil {
+					got[i] = track.RequestedID()
+				}
+			}
+			if len(tracks) > 11 && (tracks[11] == nil || tracks[11].ID != "relinkedid11") {
+				t.Errorf("Expected the relinked track at position 11, got %v", tracks[11])
+			}
+			return got, err
+		},
+	})
+}
@@ -25,10 +25,17 @@ type User struct {
 	ID string `json:"id"`
 	// The user's profile image.
 	Images []Image `json:"images"`
+	// The object type: "user".
+	Type string `json:"type"`
 	// The Spotify URI for the user.
 	URI URI `json:"uri"`
 }
 
+// FollowerCount returns the total number of followers of the user.
+func (u *User) FollowerCount() int64 {
+	return int64(u.Followers.Count)
+}
+
 // PrivateUser contains additional information about a user.
 // This data is private and requires user authentication.
 type PrivateUser struct {
@@ -73,6 +80,30 @@ func (c *Client) GetUsersPublicProfile(ctx context.Context, userID ID) (*User, e
 	return &user, nil
 }
 
+// GetUsersPublicProfiles gets the public profiles of several Spotify users,
+// keyed by user ID.  Spotify has no endpoint for fetching several users at
+// once, so the profiles are fetched individually with a bounded number of
+// concurrent requests.  If some of the profiles can't be fetched, the others
+// are still returned, along with a [BatchError] that identifies the users
+// that failed.
+func (c *Client) GetUsersPublicProfiles(ctx context.Context, userIDs ...ID) (map[ID]*User, error) {
+	users := make([]*User, len(userIDs))
+	errs := forEachConcurrent(ctx, len(userIDs), maxConcurrentRequests, func(ctx context.Context, i int) error {
+		user, err := c.GetUsersPublicProfile(ctx, userIDs[i])
+		users[i] = user
+		return err
+	})
+
+	result := make(map[ID]*User, len(userIDs))
+	for i, user := range users {
+		if user != nil {
+			result[userIDs[i]] = user
+		}
+	}
+
+	return result, newBatchError(userIDs, errs)
+}
+
 // CurrentUser gets detailed profile information about the
 // [current user].
 //
@@ -120,13 +151,47 @@ func (c *Client) CurrentUsersShows(ctx context.Context, opts ...RequestOption) (
 	return &result, nil
 }
 
+// CurrentUsersEpisodes gets a [list of episodes] saved in the current
+// Spotify user's library.  This call requires the [ScopeUserLibraryRead]
+// scope.
+//
+// Supported options: [Limit], [Market], [Offset].
+//
+// [list of episodes]: https://developer.spotify.com/documentation/web-api/reference/get-users-saved-episodes
+func (c *Client) CurrentUsersEpisodes(ctx context.Context, opts ...RequestOption) (*SavedEpisodePage, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	spotifyURL := c.baseURL + "me/episodes"
+	if params := processOptions(opts...).urlParams.Encode(); params != "" {
+		spotifyURL += "?" + params
+	}
+
+	var result SavedEpisodePage
+
+	err = c.get(ctx, spotifyURL, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
 // CurrentUsersTracks gets a [list of songs] saved in the current
 // Spotify user's "Your Music" library.
 //
-// Supported options: [Limit], [Country], [Offset].
+// Supported options: [Limit], [Market], [Offset].  For backwards compatibility,
+// a [Country] option is sent as the market.
 //
 // [list of songs]: https://developer.spotify.com/documentation/web-api/reference/get-users-saved-tracks
 func (c *Client) CurrentUsersTracks(ctx context.Context, opts ...RequestOption) (*SavedTrackPage, error) {
+	opts, err := c.marketOptions(ctx, withCountryAsMarket(opts))
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "me/tracks"
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
 		spotifyURL += "?" + params
@@ -134,7 +199,7 @@ func (c *Client) CurrentUsersTracks(ctx context.Context, opts ...RequestOption)
 
 	var result SavedTrackPage
 
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +212,7 @@ func (c *Client) CurrentUsersTracks(ctx context.Context, opts ...RequestOption)
 //
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the [ScopeUserFollowModify] scope.
+// More than 50 IDs may be passed; they are sent in batches of 50.
 //
 // [adds the current user as a follower]: https://developer.spotify.com/documentation/web-api/reference/follow-artists-users
 // [Spotify ID]: https://developer.spotify.com/documentation/web-api/#spotify-uris-and-ids
@@ -159,6 +225,7 @@ func (c *Client) FollowUser(ctx context.Context, ids ...ID) error {
 //
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the [ScopeUserFollowModify] scope.
+// More than 50 IDs may be passed; they are sent in batches of 50.
 //
 // [adds the current user as a follower]: https://developer.spotify.com/documentation/web-api/reference/follow-artists-users
 // [Spotify ID]: https://developer.spotify.com/documentation/web-api/#spotify-uris-and-ids
@@ -171,6 +238,7 @@ func (c *Client) FollowArtist(ctx context.Context, ids ...ID) error {
 //
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the [ScopeUserFollowModify] scope.
+// More than 50 IDs may be passed; they are sent in batches of 50.
 //
 // [removes the current user as a follower]: https://developer.spotify.com/documentation/web-api/reference/unfollow-artists-users
 func (c *Client) UnfollowUser(ctx context.Context, ids ...ID) error {
@@ -182,6 +250,7 @@ func (c *Client) UnfollowUser(ctx context.Context, ids ...ID) error {
 //
 // Modifying the lists of artists or users the current user follows
 // requires that the application has the [ScopeUserFollowModify] scope.
+// More than 50 IDs may be passed; they are sent in batches of 50.
 //
 // [removes the current user as a follower]: https://developer.spotify.com/documentation/web-api/reference/unfollow-artists-users
 func (c *Client) UnfollowArtist(ctx context.Context, ids ...ID) error {
@@ -196,51 +265,76 @@ func (c *Client) UnfollowArtist(ctx context.Context, ids ...ID) error {
 // "user" or "artist".
 //
 // The result is returned as a slice of bool values in the same order
-// in which the IDs were specified.
+// in which the IDs were specified.  More than 50 IDs may be passed; they
+// are checked in batches of 50.
 //
 // [checks to see if the current user is following]: https://developer.spotify.com/documentation/web-api/reference/check-current-user-follows
 func (c *Client) CurrentUserFollows(ctx context.Context, t string, ids ...ID) ([]bool, error) {
-	if l := len(ids); l == 0 || l > 50 {
-		return nil, errors.New("spotify: UserFollows supports 1 to 50 IDs")
+	if len(ids) == 0 {
+		return nil, errors.New("spotify: at least one ID is required")
 	}
 	if t != "artist" && t != "user" {
 		return nil, errors.New("spotify: t must be 'artist' or 'user'")
 	}
-	spotifyURL := fmt.Sprintf("%sme/following/contains?type=%s&ids=%s",
-		c.baseURL, t, strings.Join(toStringSlice(ids), ","))
+	if err := c.checkChunkSize(len(ids), 50); err != nil {
+		return nil, err
+	}
 
-	var result []bool
+	result := make([]bool, 0, len(ids))
+	for _, chunk := range chunkIDs(ids, 50) {
+		spotifyURL := fmt.Sprintf("%sme/following/contains?type=%s&ids=%s",
+			c.baseURL, t, strings.Join(toStringSlice(chunk), ","))
 
-	err := c.get(ctx, spotifyURL, &result)
-	if err != nil {
-		return nil, err
+		var follows []bool
+
+		err := c.get(ctx, spotifyURL, &follows)
+		if err != nil {
+			return nil, err
+		}
+		if len(follows) != len(chunk) {
+			return nil, fmt.Errorf("spotify: expected %d results, got %d", len(chunk), len(follows))
+		}
+
+		result = append(result, follows...)
 	}
 
 	return result, nil
 }
 
 func (c *Client) modifyFollowers(ctx context.Context, usertype string, follow bool, ids ...ID) error {
-	if l := len(ids); l == 0 || l > 50 {
-		return errors.New("spotify: Follow/Unfollow supports 1 to 50 IDs")
+	if len(ids) == 0 {
+		return errors.New("spotify: at least one ID is required")
 	}
-	v := url.Values{}
-	v.Add("type", usertype)
-	v.Add("ids", strings.Join(toStringSlice(ids), ","))
-	spotifyURL := c.baseURL + "me/following?" + v.Encode()
+	if err := c.checkChunkSize(len(ids), 50); err != nil {
+		return err
+	}
+
 	method := "PUT"
 	if !follow {
 		method = "DELETE"
 	}
-	req, err := http.NewRequestWithContext(ctx, method, spotifyURL, nil)
-	if err != nil {
-		return err
+	for _, chunk := range chunkIDs(ids, 50) {
+		v := url.Values{}
+		v.Add("type", usertype)
+		v.Add("ids", strings.Join(toStringSlice(chunk), ","))
+		spotifyURL := c.baseURL + "me/following?" + v.Encode()
+		req, err := http.NewRequestWithContext(ctx, method, spotifyURL, nil)
+		if err != nil {
+			return err
+		}
+		if err := c.execute(req, nil, http.StatusNoContent); err != nil {
+			return err
+		}
 	}
-	return c.execute(req, nil, http.StatusNoContent)
+	return nil
 }
 
 // CurrentUsersFollowedArtists gets the [current user's followed artists].
 // This call requires that the user has granted the [ScopeUserFollowRead] scope.
 //
+// The result is cursor-based: use [Client.NextFollowedArtists] to fetch the
+// following pages, or pass the page's Cursor.After to the [After] option.
+//
 // Supported options: [Limit], [After].
 //
 // [current user's followed artists]: https://developer.spotify.com/documentation/web-api/reference/get-followed
@@ -264,6 +358,36 @@ func (c *Client) CurrentUsersFollowedArtists(ctx context.Context, opts ...Reques
 	return &result.A, nil
 }
 
+// NextFollowedArtists fetches the page of followed artists that comes after
+// p, which was returned by [Client.CurrentUsersFollowedArtists], and writes
+// it into p.  It returns [ErrNoMorePages] if p is the last page.  The next
+// URL is checked like it is by [Client.NextPage], which can't be used for
+// this page because Spotify wraps it in an "artists" object.
+func (c *Client) NextFollowedArtists(ctx context.Context, p *FullArtistCursorPage) error {
+	if p == nil {
+		return errors.New("spotify: p must be a non-nil pointer to a page")
+	}
+	if p.Next == "" || p.Cursor.After == "" {
+		return ErrNoMorePages
+	}
+	nextURL, err := c.pageURL(p.Next)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		A FullArtistCursorPage `json:"artists"`
+	}
+
+	err = c.get(ctx, nextURL, &result)
+	if err != nil {
+		return err
+	}
+
+	*p = result.A
+	return nil
+}
+
 // CurrentUsersAlbums gets a [list of albums] saved in the current
 // Spotify user's "Your Music" library.
 //
@@ -271,6 +395,11 @@ func (c *Client) CurrentUsersFollowedArtists(ctx context.Context, opts ...Reques
 //
 // [list of albums]: https://developer.spotify.com/documentation/web-api/reference/get-users-saved-albums
 func (c *Client) CurrentUsersAlbums(ctx context.Context, opts ...RequestOption) (*SavedAlbumPage, error) {
+	opts, err := c.marketOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	spotifyURL := c.baseURL + "me/albums"
 	if params := processOptions(opts...).urlParams.Encode(); params != "" {
 		spotifyURL += "?" + params
@@ -278,7 +407,7 @@ func (c *Client) CurrentUsersAlbums(ctx context.Context, opts ...RequestOption)
 
 	var result SavedAlbumPage
 
-	err := c.get(ctx, spotifyURL, &result)
+	err = c.get(ctx, spotifyURL, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -313,10 +442,11 @@ func (c *Client) CurrentUsersPlaylists(ctx context.Context, opts ...RequestOptio
 	return &result, nil
 }
 
-// CurrentUsersTopArtists fetches a list of the [user's top artists] over the specified [Timerange].
-// The default is [MediumTermRange].
+// CurrentUsersTopArtists fetches a list of the [user's top artists] over the
+// specified [Timerange]. The default limit is 20 and the default timerange is
+// [MediumTermRange]. This call requires [ScopeUserTopRead].
 //
-// Supported options: [Limit], [Timerange]
+// Supported options: [Limit], [Timerange], [Offset].
 //
 // [user's top artists]: https://developer.spotify.com/documentation/web-api/reference/get-users-top-artists-and-tracks
 func (c *Client) CurrentUsersTopArtists(ctx context.Context, opts ...RequestOption) (*FullArtistPage, error) {
@@ -335,8 +465,8 @@ func (c *Client) CurrentUsersTopArtists(ctx context.Context, opts ...RequestOpti
 	return &result, nil
 }
 
-// CurrentUsersTopTracks fetches the [user's top tracks] over the specified
-// [Timerange]. The default limit is 20 and the default timerange is
+// CurrentUsersTopTracks fetches a list of the [user's top tracks] over the
+// specified [Timerange]. The default limit is 20 and the default timerange is
 // [MediumTermRange]. This call requires [ScopeUserTopRead].
 //
 // Supported options: [Limit], [Timerange], [Offset].
@@ -2,12 +2,17 @@ package spotify
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 const userResponse = `
@@ -46,6 +51,40 @@ func TestUserProfile(t *testing.T) {
 	if f := user.Followers.Count; f != 3829 {
 		t.Errorf("Expected 3829 followers, got %d\n", f)
 	}
+	if f := user.FollowerCount(); f != 3829 {
+		t.Errorf("Expected FollowerCount 3829, got %d\n", f)
+	}
+}
+
+func TestGetUsersPublicProfiles(t *testing.T) {
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/users/")
+		if id == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{ "error": { "status": 404, "message": "No such user" } }`)
+			return
+		}
+		fmt.Fprintf(w, `{ "id": "%s", "display_name": "User %s" }`, id, id)
+	}))
+	defer server.Close()
+
+	users, err := client.GetUsersPublicProfiles(context.Background(), "alice", "missing", "bob")
+	var be BatchError
+	if !errors.As(err, &be) {
+		t.Fatal("Expected a BatchError, got", err)
+	}
+	if len(be.Errors) != 1 || be.Errors[0].ID != "missing" || be.Errors[0].Index != 1 {
+		t.Errorf("Unexpected item errors: %v", be.Errors)
+	}
+	if len(users) != 2 {
+		t.Fatalf("Expected 2 users, got %d", len(users))
+	}
+	if u := users["bob"]; u == nil || u.DisplayName != "User bob" {
+		t.Error("Expected bob's profile, got", u)
+	}
+	if _, ok := users["missing"]; ok {
+		t.Error("Expected the missing user to be omitted")
+	}
 }
 
 func TestCurrentUser(t *testing.T) {
@@ -190,6 +229,67 @@ func TestUserFollows(t *testing.T) {
 	}
 }
 
+func TestFollowInChunks(t *testing.T) {
+	var requests []string
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		ids := strings.Split(query.Get("ids"), ",")
+		requests = append(requests, fmt.Sprintf("%s %s %s %d", r.Method, r.URL.Path, query.Get("type"), len(ids)))
+		if strings.HasSuffix(r.URL.Path, "/contains") {
+			// users with an even number in their ID are followed
+			follows := make([]bool, len(ids))
+			for i, id := range ids {
+				n, _ := strconv.Atoi(strings.TrimPrefix(id, "user"))
+				follows[i] = n%2 == 0
+			}
+			json.NewEncoder(w).Encode(follows)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ids := make([]ID, 60)
+	for i := range ids {
+		ids[i] = ID(fmt.Sprintf("user%d", i))
+	}
+
+	if err := client.FollowUser(context.Background(), ids...); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UnfollowArtist(context.Background(), ids...); err != nil {
+		t.Fatal(err)
+	}
+	follows, err := client.CurrentUserFollows(context.Background(), "user", ids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PUT /me/following user 50",
+		"PUT /me/following user 10",
+		"DELETE /me/following artist 50",
+		"DELETE /me/following artist 10",
+		"GET /me/following/contains user 50",
+		"GET /me/following/contains user 10",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+	if len(follows) != len(ids) {
+		t.Fatalf("Expected %d results, got %d", len(ids), len(follows))
+	}
+	for i, follow := range follows {
+		if follow != (i%2 == 0) {
+			t.Errorf("Wrong result for user%d: %v", i, follow)
+		}
+	}
+
+	if err := client.FollowArtist(context.Background()); err == nil {
+		t.Error("Expected an error without IDs")
+	}
+}
+
 func TestCurrentUsersTracks(t *testing.T) {
 	client, server := testClientFile(http.StatusOK, "test_data/current_users_tracks.txt")
 	defer server.Close()
@@ -218,6 +318,13 @@ func TestCurrentUsersTracks(t *testing.T) {
 		t.Errorf("Expected '%s', got '%s'\n", expected, tracks.Tracks[0].Name)
 		fmt.Printf("\n%#v\n", tracks.Tracks[0])
 	}
+	added, err := time.Parse(TimestampLayout, tracks.Tracks[0].AddedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2015, 1, 31, 0, 37, 55, 0, time.UTC); !added.Equal(want) {
+		t.Errorf("Expected the track to be added at %v, got %v", want, added)
+	}
 }
 
 func TestCurrentUsersAlbums(t *testing.T) {
@@ -452,3 +559,83 @@ func TestCurrentUsersTopTracks(t *testing.T) {
 		t.Errorf("Wrong ISRC: want %s, got %s\n", isrc, i)
 	}
 }
+
+func TestCurrentUsersTopOptions(t *testing.T) {
+	for _, path := range []string{"/me/top/artists", "/me/top/tracks"} {
+		client, server := testClientString(http.StatusOK, `{ "items": [], "limit": 5, "offset": 10 }`, func(r *http.Request) {
+			if r.URL.Path != path {
+				t.Errorf("Expected path %s, got %s", path, r.URL.Path)
+			}
+			query := r.URL.Query()
+			if got := query.Get("time_range"); got != string(ShortTermRange) {
+				t.Errorf("Expected time_range %s, got %s", ShortTermRange, got)
+			}
+			if query.Get("limit") != "5" || query.Get("offset") != "10" {
+				t.Errorf("Expected limit 5 and offset 10, got %s", r.URL.RawQuery)
+			}
+		})
+
+		var err error
+		if path == "/me/top/artists" {
+			_, err = client.CurrentUsersTopArtists(context.Background(), Timerange(ShortTermRange), Limit(5), Offset(10))
+		} else {
+			_, err = client.CurrentUsersTopTracks(context.Background(), Timerange(ShortTermRange), Limit(5), Offset(10))
+		}
+		server.Close()
+		if err != nil {
+			t.Error(path, err)
+		}
+	}
+}
+
+func TestNextFollowedArtists(t *testing.T) {
+	var server *httptest.Server
+	client, server := testClientHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if typ := r.URL.Query().Get("type"); typ != "artist" {
+			t.Errorf("Expected type artist, got %s", typ)
+		}
+		switch after := r.URL.Query().Get("after"); after {
+		case "":
+			fmt.Fprintf(w, `{ "artists": {
+				"items": [ { "id": "artist1" }, { "id": "artist2" } ],
+				"next": "%s/me/following?type=artist&after=artist2&limit=2",
+				"cursors": { "after": "artist2" },
+				"limit": 2,
+				"total": 3
+			} }`, server.URL)
+		case "artist2":
+			fmt.Fprint(w, `{ "artists": {
+				"items": [ { "id": "artist3" } ],
+				"next": null,
+				"cursors": { "after": null },
+				"limit": 2,
+				"total": 3
+			} }`)
+		default:
+			t.Errorf("Unexpected cursor %s", after)
+		}
+	}))
+	defer server.Close()
+
+	page, err := client.CurrentUsersFollowedArtists(context.Background(), Limit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []ID
+	for {
+		for _, artist := range page.Artists {
+			ids = append(ids, artist.ID)
+		}
+		err := client.NextFollowedArtists(context.Background(), page)
+		if err == ErrNoMorePages {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if expected := []ID{"artist1", "artist2", "artist3"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+}